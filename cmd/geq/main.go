@@ -1,23 +1,181 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"runtime/debug"
+	"strings"
 
 	"github.com/ryym/geq/internal/codegen"
 )
 
+// exit codes
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+const usage = `Usage: geq <command> [flags] [root]
+
+Commands:
+  gen      generate geq.gen.go for each geqbld.go under root (default ".")
+  check    check that the generated files are up to date, printing the differences
+  version  print the version of geq
+
+Run "geq <command> -h" for the flags of each command.
+`
+
 func main() {
-	flag.Parse()
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+	switch cmd := args[0]; cmd {
+	case "gen":
+		return runGen(args[1:], stdout, stderr)
+	case "check":
+		return runCheck(args[1:], stdout, stderr)
+	case "version":
+		return runVersion(args[1:], stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return exitOK
+	default:
+		// Keep "geq <root>" working as "geq gen <root>".
+		if info, err := os.Stat(cmd); err == nil && info.IsDir() {
+			return runGen(args, stdout, stderr)
+		}
+		fmt.Fprintf(stderr, "geq: unknown command %q\n\n%s", cmd, usage)
+		return exitUsage
+	}
+}
 
-	cfg := &codegen.Config{
-		RootPath: flag.Args()[0],
+func runGen(args []string, stdout, stderr io.Writer) int {
+	fs := newFlagSet("gen", "Generate geq.gen.go for each geqbld.go under root.", stderr)
+	cfg := &codegen.Config{}
+	verbose := addConfigFlags(fs, cfg)
+	check := fs.Bool("check", false, "check that the generated files are up to date instead of writing them (same as geq check)")
+	root, code, ok := parseArgs(fs, args)
+	if !ok {
+		return code
+	}
+	cfg.RootPath = root
+	if *verbose {
+		cfg.Log = stderr
+	}
+	if *check {
+		return checkFiles(cfg, stdout, stderr)
 	}
 
 	err := codegen.Run(cfg)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		fmt.Fprintf(stderr, "geq: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+func runCheck(args []string, stdout, stderr io.Writer) int {
+	fs := newFlagSet("check", "Check that the generated files are up to date without writing them.\n"+
+		"The differences are printed as unified diffs and the exit code is 1 if any file is outdated.\n"+
+		"Generated files no longer generated by any geqbld.go are reported as well,\n"+
+		"except when -pkg is given since files of the other packages are not checked.", stderr)
+	cfg := &codegen.Config{}
+	verbose := addConfigFlags(fs, cfg)
+	root, code, ok := parseArgs(fs, args)
+	if !ok {
+		return code
+	}
+	cfg.RootPath = root
+	if *verbose {
+		cfg.Log = stderr
+	}
+	return checkFiles(cfg, stdout, stderr)
+}
+
+func checkFiles(cfg *codegen.Config, stdout, stderr io.Writer) int {
+	err := codegen.Check(cfg, stdout)
+	if err != nil {
+		if errors.Is(err, codegen.ErrOutdated) {
+			fmt.Fprintln(stderr, "geq: generated files are not up to date, run geq gen")
+		} else {
+			fmt.Fprintf(stderr, "geq: %v\n", err)
+		}
+		return exitError
+	}
+	return exitOK
+}
+
+func runVersion(args []string, stdout, stderr io.Writer) int {
+	fs := newFlagSet("version", "Print the version of geq.", stderr)
+	err := fs.Parse(args)
+	if err != nil {
+		return parseErrCode(err)
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return exitUsage
+	}
+	version := "(devel)"
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		version = info.Main.Version
+	}
+	fmt.Fprintf(stdout, "geq %s\n", version)
+	return exitOK
+}
+
+func newFlagSet(cmd, desc string, output io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet("geq "+cmd, flag.ContinueOnError)
+	fs.SetOutput(output)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: geq %s [flags] [root]\n\n%s\n\nFlags:\n", cmd, desc)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+func addConfigFlags(fs *flag.FlagSet, cfg *codegen.Config) (verbose *bool) {
+	fs.StringVar(&cfg.OutDir, "outdir", "", "output directory relative to each geqbld.go (overrides geq:outdir)")
+	fs.StringVar(&cfg.FileName, "file", "geq.gen.go", "output file name")
+	fs.Func("pkg", "generate only packages matching the import path pattern such as example.com/app/... (repeatable)", func(v string) error {
+		for _, p := range strings.Split(v, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				cfg.Packages = append(cfg.Packages, p)
+			}
+		}
+		return nil
+	})
+	return fs.Bool("v", false, "print progress")
+}
+
+// parseArgs parses the flags and returns the root path.
+func parseArgs(fs *flag.FlagSet, args []string) (root string, code int, ok bool) {
+	err := fs.Parse(args)
+	if err != nil {
+		return "", parseErrCode(err), false
+	}
+	switch fs.NArg() {
+	case 0:
+		return ".", exitOK, true
+	case 1:
+		return fs.Arg(0), exitOK, true
+	default:
+		fs.Usage()
+		return "", exitUsage, false
+	}
+}
+
+func parseErrCode(err error) int {
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
 	}
+	return exitUsage
 }
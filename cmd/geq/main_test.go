@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRunExitCodes(t *testing.T) {
+	for _, c := range []struct {
+		args []string
+		code int
+	}{
+		{nil, exitUsage},
+		{[]string{"--help"}, exitOK},
+		{[]string{"unknown"}, exitUsage},
+		{[]string{"gen", "-h"}, exitOK},
+		{[]string{"gen", "-unknown"}, exitUsage},
+		{[]string{"gen", "a", "b"}, exitUsage},
+		{[]string{"gen", "not-exist"}, exitError},
+		{[]string{"version", "x"}, exitUsage},
+		{[]string{"check", "-h"}, exitOK},
+		{[]string{"check", "../../examples/helloworld"}, exitOK},
+		{[]string{"check", "-file", "missing.gen.go", "../../examples/helloworld"}, exitError},
+		{[]string{"gen", "-check", "-file", "missing.gen.go", "../../examples/helloworld"}, exitError},
+	} {
+		var stdout, stderr bytes.Buffer
+		code := run(c.args, &stdout, &stderr)
+		if code != c.code {
+			t.Errorf("%v: exit code %d, want %d\n%s", c.args, code, c.code, stderr.String())
+		}
+	}
+}
+
+func TestRunVersion(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"version"}, &stdout, &stderr)
+	if code != exitOK || !strings.HasPrefix(stdout.String(), "geq ") {
+		t.Errorf("unexpected version output: %d %q", code, stdout.String())
+	}
+}
@@ -3,20 +3,100 @@ package geq
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"slices"
 )
 
 type DeleteQuery struct {
 	table  AnyTable
 	wheres []Expr
+	using  []TableLike
+	joins  []joinClause
+	orders []Orderer
+	limit  uint
+	all    bool
+	hard   bool
 }
 
 func newDeleteQuery(table AnyTable) *DeleteQuery {
 	return &DeleteQuery{table: table, wheres: nil}
 }
 
+// Clone returns a copy of the query that shares no mutable state with the original.
+func (q *DeleteQuery) Clone() *DeleteQuery {
+	c := *q
+	c.wheres = slices.Clone(q.wheres)
+	c.using = slices.Clone(q.using)
+	c.joins = slices.Clone(q.joins)
+	c.orders = slices.Clone(q.orders)
+	return &c
+}
+
 func (q *DeleteQuery) Where(exprs ...Expr) *DeleteQuery {
-	q.wheres = append(q.wheres, exprs...)
-	return q
+	c := q.Clone()
+	c.wheres = append(c.wheres, exprs...)
+	return c
+}
+
+// Using adds tables referenced by the delete conditions.
+// It is rendered as DELETE ... USING on PostgreSQL and DELETE a FROM a, b ... on MySQL.
+func (q *DeleteQuery) Using(tables ...TableLike) *DeleteQuery {
+	c := q.Clone()
+	c.using = append(c.using, tables...)
+	return c
+}
+
+// InnerJoin joins a table to delete rows. Only dialects such as MySQL support it.
+func (q *DeleteQuery) InnerJoin(table TableLike, condition Expr) *DeleteQuery {
+	return q.join(joinClause{mode: "INNER", table: table, condition: condition})
+}
+
+// LeftJoin joins a table to delete rows. Only dialects such as MySQL support it.
+func (q *DeleteQuery) LeftJoin(table TableLike, condition Expr) *DeleteQuery {
+	return q.join(joinClause{mode: "LEFT", table: table, condition: condition})
+}
+
+func (q *DeleteQuery) JoinRels(relships ...AnyRelship) *DeleteQuery {
+	joins := make([]joinClause, 0, len(relships))
+	for _, rs := range relships {
+		joins = append(joins, rs.toJoinClauses("INNER")...)
+	}
+	return q.join(joins...)
+}
+
+func (q *DeleteQuery) join(joins ...joinClause) *DeleteQuery {
+	c := q.Clone()
+	c.joins = append(c.joins, joins...)
+	return c
+}
+
+// OrderBy sets the order to delete rows. Only dialects such as MySQL support it.
+func (q *DeleteQuery) OrderBy(orders ...Orderer) *DeleteQuery {
+	c := q.Clone()
+	c.orders = slices.Clone(orders)
+	return c
+}
+
+// Limit limits the number of rows to delete. Only dialects such as MySQL support it.
+func (q *DeleteQuery) Limit(n uint) *DeleteQuery {
+	c := q.Clone()
+	c.limit = n
+	return c
+}
+
+// All allows the query to delete all rows without WHERE conditions.
+// Otherwise the query without WHERE conditions fails to build.
+func (q *DeleteQuery) All() *DeleteQuery {
+	c := q.Clone()
+	c.all = true
+	return c
+}
+
+// HardDelete deletes rows physically even if the table supports soft delete.
+func (q *DeleteQuery) HardDelete() *DeleteQuery {
+	c := q.Clone()
+	c.hard = true
+	return c
 }
 
 func (q *DeleteQuery) Build() (bq *BuiltQuery, err error) {
@@ -25,15 +105,75 @@ func (q *DeleteQuery) Build() (bq *BuiltQuery, err error) {
 }
 
 func (q *DeleteQuery) BuildWith(cfg *QueryConfig) (bq *BuiltQuery, err error) {
+	if col := q.table.getSoftDelete(); col != nil && !q.hard {
+		if andAll(q.wheres...) == nil && !q.all {
+			return nil, errors.New("[geq.Delete] WHERE conditions empty, use All to delete all rows")
+		}
+		return q.softDeleteQuery(col).BuildWith(cfg)
+	}
+
+	style := modifyStyle(cfg.dialect)
+	multiTable := len(q.using) > 0 || len(q.joins) > 0
+
 	w := newQueryWriter()
-	w.Write("DELETE FROM ")
-	w.Write(cfg.dialect.Ident(q.table.getTableName()))
+	switch style {
+	case ModifyJoin:
+		if multiTable {
+			// The target is referred by the alias if any.
+			target := qualifiedTableName(q.table, cfg)
+			if alias := q.table.getAlias(); alias != "" {
+				target = cfg.dialect.Ident(alias)
+			}
+			w.Printf("DELETE %s FROM ", target)
+			q.table.appendTable(w, cfg)
+			for _, t := range q.using {
+				w.Write(", ")
+				t.appendTable(w, cfg)
+			}
+			writeJoins(w, cfg, "geq.Delete", q.joins)
+		} else {
+			w.Write("DELETE FROM ")
+			q.table.appendTable(w, cfg)
+		}
+	default:
+		w.Write("DELETE FROM ")
+		q.table.appendTable(w, cfg)
+		if len(q.joins) > 0 {
+			w.AddErr(errors.New("[geq.Delete] JOIN is not supported by the dialect, use Using instead"))
+		}
+		if len(q.using) > 0 {
+			w.Write(" USING ")
+			for i, t := range q.using {
+				if i > 0 {
+					w.Write(", ")
+				}
+				t.appendTable(w, cfg)
+			}
+		}
+	}
+
+	if !writeConditions(w, cfg, " WHERE ", q.wheres) && !q.all {
+		w.AddErr(errors.New("[geq.Delete] WHERE conditions empty, use All to delete all rows"))
+	}
 
-	if len(q.wheres) > 0 {
-		w.Write(" WHERE ")
-		andAll(q.wheres...).appendExpr(w, cfg)
+	if len(q.orders) > 0 || q.limit > 0 {
+		switch {
+		case style != ModifyJoin:
+			w.AddErr(errors.New("[geq.Delete] ORDER BY and LIMIT are not supported by the dialect"))
+		case multiTable:
+			w.AddErr(errors.New("[geq.Delete] ORDER BY and LIMIT cannot be used with multiple tables"))
+		}
+	}
+	if len(q.orders) > 0 {
+		writeOrders(w, cfg, q.orders)
+	}
+	if q.limit > 0 {
+		w.Printf(" LIMIT %d", q.limit)
 	}
 
+	if err := w.Err(); err != nil {
+		return nil, err
+	}
 	return &BuiltQuery{Query: w.String(), Args: w.Args}, nil
 }
 
@@ -44,3 +184,88 @@ func (q *DeleteQuery) Exec(ctx context.Context, db QueryExecutor) (result sql.Re
 	}
 	return db.ExecContext(ctx, bq.Query, bq.Args...)
 }
+
+// softDeleteQuery returns a query to set the deletion time of rows not deleted yet.
+func (q *DeleteQuery) softDeleteQuery(col AnyColumn) *UpdateQuery {
+	return &UpdateQuery{
+		table:    q.table,
+		valueMap: map[AnyColumn]Expr{col: toExpr(currentTime())},
+		wheres:   append(slices.Clone(q.wheres), col.IsNull()),
+		from:     q.using,
+		joins:    q.joins,
+		orders:   q.orders,
+		limit:    q.limit,
+		all:      true,
+	}
+}
+
+// DeleteInBatches deletes the rows of the query by up to batchSize rows repeatedly
+// until no rows are affected, and returns the total number of deleted rows.
+func DeleteInBatches(ctx context.Context, db QueryExecutor, q *DeleteQuery, batchSize uint) (deleted int64, err error) {
+	if batchSize == 0 {
+		return 0, errors.New("[geq.DeleteInBatches] batch size must be positive")
+	}
+	bq, err := q.inBatches(defaultDialect, batchSize)
+	if err != nil {
+		return 0, err
+	}
+	for {
+		result, err := bq.Exec(ctx, db)
+		if err != nil {
+			return deleted, err
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return deleted, err
+		}
+		if n == 0 {
+			return deleted, nil
+		}
+		deleted += n
+	}
+}
+
+// inBatches returns a query to delete up to n rows. If the dialect does not support
+// DELETE ... LIMIT, it deletes rows by their primary keys selected with LIMIT instead.
+func (q *DeleteQuery) inBatches(d Dialect, n uint) (*DeleteQuery, error) {
+	if andAll(q.wheres...) == nil && !q.all {
+		return nil, errors.New("[geq.Delete] WHERE conditions empty, use All to delete all rows")
+	}
+	if len(q.using) == 0 && len(q.joins) == 0 && modifyStyle(d) == ModifyJoin {
+		return q.Limit(n), nil
+	}
+	if modifyStyle(d) == ModifyJoin {
+		return nil, errors.New("[geq.DeleteInBatches] multiple tables cannot be deleted in batches by the dialect")
+	}
+
+	pks := q.table.getPKs()
+	if len(pks) == 0 {
+		return nil, errors.New("[geq.DeleteInBatches] primary key of the table unknown")
+	}
+	sels := make([]Selection, 0, len(pks))
+	keys := make([]Expr, 0, len(pks))
+	for _, pk := range pks {
+		sels = append(sels, pk)
+		keys = append(keys, pk)
+	}
+	// Select only the rows to be deleted, in the same way as the delete query.
+	sub := Select(sels...).From(q.table).WithDeleted()
+	for _, t := range q.using {
+		sub = sub.CrossJoin(t)
+	}
+	sub = sub.join(q.joins...).Where(q.wheres...)
+	if col := q.table.getSoftDelete(); col != nil && !q.hard {
+		sub = sub.Where(col.IsNull())
+	}
+	sub = sub.OrderBy(q.orders...).Limit(n)
+
+	var operand Expr = pks[0]
+	if len(pks) > 1 {
+		operand = implOps(&rowExpr{exprs: keys})
+	}
+	return &DeleteQuery{
+		table:  q.table,
+		wheres: []Expr{implOps(&inQueryExpr{operand: operand, query: sub})},
+		hard:   q.hard,
+	}, nil
+}
@@ -1,6 +1,9 @@
 package geq
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 type StrConcatType uint
 
@@ -9,12 +12,58 @@ const (
 	StrConcatFunc
 )
 
+type RowCompareType uint
+
+const (
+	// RowCompareTuple compares row values directly, such as (a, b) > (?, ?).
+	RowCompareTuple RowCompareType = iota
+	// RowCompareExpanded expands row value comparisons, such as a > ? OR a = ? AND b > ?.
+	RowCompareExpanded
+)
+
+type ModifyStyle uint
+
+const (
+	// ModifyStandard adds other tables by UPDATE ... FROM or DELETE ... USING,
+	// and does not support ORDER BY or LIMIT in UPDATE and DELETE.
+	ModifyStandard ModifyStyle = iota
+	// ModifyJoin adds other tables by joins, such as UPDATE a INNER JOIN b ON ... SET ...,
+	// and supports ORDER BY and LIMIT in single table UPDATE and DELETE.
+	ModifyJoin
+)
+
 type Dialect interface {
 	Placeholder(typeName string, prevArgs []any) string
 	Ident(v string) string
 	StrConcatType() StrConcatType
 }
 
+// RowComparer is an optional interface of Dialect to specify how to compare row values.
+// RowCompareTuple is used for dialects which do not implement it.
+type RowComparer interface {
+	RowCompareType() RowCompareType
+}
+
+// ModifyStyler is an optional interface of Dialect to specify how to modify rows of multiple tables.
+// ModifyStandard is used for dialects which do not implement it.
+type ModifyStyler interface {
+	ModifyStyle() ModifyStyle
+}
+
+func modifyStyle(d Dialect) ModifyStyle {
+	if ms, ok := d.(ModifyStyler); ok {
+		return ms.ModifyStyle()
+	}
+	return ModifyStandard
+}
+
+func rowCompareType(d Dialect) RowCompareType {
+	if rc, ok := d.(RowComparer); ok {
+		return rc.RowCompareType()
+	}
+	return RowCompareTuple
+}
+
 func DialectByName(driverName string) (d Dialect, err error) {
 	switch driverName {
 	case "postgres":
@@ -40,7 +89,19 @@ func (d *DialectGeneric) StrConcatType() StrConcatType {
 	return StrConcatStandard
 }
 
-type DialectPostgres struct{}
+func (d *DialectGeneric) RowCompareType() RowCompareType {
+	return RowCompareTuple
+}
+
+func (d *DialectGeneric) ModifyStyle() ModifyStyle {
+	return ModifyStandard
+}
+
+type DialectPostgres struct {
+	// MinimalQuoting makes Ident quote identifiers only when required,
+	// i.e. when they are reserved words or contain special characters.
+	MinimalQuoting bool
+}
 
 func (d *DialectPostgres) Placeholder(typeName string, prevArgs []any) string {
 	phNum := len(prevArgs) + 1
@@ -51,23 +112,87 @@ func (d *DialectPostgres) Placeholder(typeName string, prevArgs []any) string {
 }
 
 func (d *DialectPostgres) Ident(v string) string {
-	return fmt.Sprintf(`"%s"`, v)
+	if d.MinimalQuoting && !identNeedsQuote(v) {
+		return v
+	}
+	return `"` + strings.ReplaceAll(v, `"`, `""`) + `"`
 }
 
 func (d *DialectPostgres) StrConcatType() StrConcatType {
 	return StrConcatStandard
 }
 
-type DialectMySQL struct{}
+func (d *DialectPostgres) RowCompareType() RowCompareType {
+	return RowCompareTuple
+}
+
+func (d *DialectPostgres) ModifyStyle() ModifyStyle {
+	return ModifyStandard
+}
+
+type DialectMySQL struct {
+	// MinimalQuoting makes Ident quote identifiers only when required,
+	// i.e. when they are reserved words or contain special characters.
+	MinimalQuoting bool
+}
 
 func (d *DialectMySQL) Placeholder(typeName string, prevArgs []any) string {
 	return "?"
 }
 
 func (d *DialectMySQL) Ident(v string) string {
-	return fmt.Sprintf("`%s`", v)
+	if d.MinimalQuoting && !identNeedsQuote(v) {
+		return v
+	}
+	return "`" + strings.ReplaceAll(v, "`", "``") + "`"
 }
 
 func (d *DialectMySQL) StrConcatType() StrConcatType {
 	return StrConcatFunc
 }
+
+func (d *DialectMySQL) RowCompareType() RowCompareType {
+	return RowCompareExpanded
+}
+
+func (d *DialectMySQL) ModifyStyle() ModifyStyle {
+	return ModifyJoin
+}
+
+// identNeedsQuote reports whether the identifier cannot be written as is.
+// Only lower case identifiers are treated as safe because PostgreSQL folds unquoted names.
+func identNeedsQuote(v string) bool {
+	if v == "" {
+		return true
+	}
+	for i, c := range v {
+		switch {
+		case c >= 'a' && c <= 'z', c == '_':
+		case c >= '0' && c <= '9' && i > 0:
+		default:
+			return true
+		}
+	}
+	_, reserved := reservedWords[v]
+	return reserved
+}
+
+// reservedWords lists keywords commonly reserved in PostgreSQL and MySQL.
+var reservedWords = map[string]struct{}{
+	"all": {}, "analyze": {}, "and": {}, "any": {}, "array": {}, "as": {}, "asc": {},
+	"between": {}, "both": {}, "by": {}, "case": {}, "cast": {}, "check": {}, "collate": {},
+	"column": {}, "constraint": {}, "create": {}, "cross": {}, "current_date": {},
+	"current_time": {}, "current_timestamp": {}, "current_user": {}, "database": {},
+	"default": {}, "delete": {}, "desc": {}, "distinct": {}, "div": {}, "do": {}, "drop": {},
+	"else": {}, "end": {}, "except": {}, "exists": {}, "false": {}, "fetch": {}, "for": {},
+	"foreign": {}, "from": {}, "full": {}, "grant": {}, "group": {}, "groups": {}, "having": {}, "in": {},
+	"index": {}, "inner": {}, "insert": {}, "intersect": {}, "interval": {}, "into": {},
+	"is": {}, "join": {}, "key": {}, "keys": {}, "leading": {}, "left": {}, "like": {},
+	"limit": {}, "localtime": {}, "localtimestamp": {}, "match": {}, "natural": {}, "not": {},
+	"null": {}, "offset": {}, "on": {}, "only": {}, "or": {}, "order": {}, "outer": {},
+	"primary": {}, "range": {}, "references": {}, "returning": {}, "right": {}, "row": {},
+	"rows": {}, "schema": {}, "select": {}, "session_user": {}, "set": {}, "some": {},
+	"table": {}, "then": {}, "to": {}, "trailing": {}, "true": {}, "union": {}, "unique": {},
+	"update": {}, "usage": {}, "user": {}, "using": {}, "values": {}, "when": {}, "where": {},
+	"window": {}, "with": {},
+}
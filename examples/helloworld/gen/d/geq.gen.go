@@ -6,6 +6,7 @@ package d
 import (
 	"github.com/ryym/geq"
 	"github.com/ryym/geq/examples/helloworld/mdl"
+	"sync"
 )
 
 var Users = NewUsers("users")
@@ -22,56 +23,74 @@ func init() {
 
 type TableUsers struct {
 	*geq.TableBase
-	relshipsSet bool
-	alias       string
-	ID          *geq.Column[uint64]
-	Name        *geq.Column[string]
-	Posts       *geq.Relship[*TablePosts, mdl.Post, uint64]
+	relshipsOnce sync.Once
+	alias        string
+	schema       string
+	ID           *geq.Column[uint64]
+	Name         *geq.Column[string]
+	Posts        *geq.Relship[*TablePosts, mdl.Post, uint64]
 }
 
 func NewUsers(alias string) *TableUsers {
+	return newUsers(alias, "")
+}
+
+func newUsers(alias, schema string) *TableUsers {
 	t := &TableUsers{
-		alias: alias,
-		ID:    geq.NewColumn[uint64](alias, "id"),
-		Name:  geq.NewColumn[string](alias, "name"),
+		alias:  alias,
+		schema: schema,
+		ID:     geq.NewColumn[uint64](alias, "id"),
+		Name:   geq.NewColumn[string](alias, "name"),
 	}
 	columns := []geq.AnyColumn{t.ID, t.Name}
 	sels := []geq.Selection{t.ID, t.Name}
-	t.TableBase = geq.NewTableBase("users", alias, columns, sels)
+	t.TableBase = geq.NewTableBase(schema, "users", alias, columns, sels, geq.TableOptions{
+		PKs: []geq.AnyColumn{t.ID},
+	})
 	return t
 }
 
 func (t *TableUsers) InitRelships() {
-	if t.relshipsSet {
-		return
-	}
-	func() {
-		r := NewPosts(t.alias + "_posts")
-		t.Posts = geq.NewRelship(r, t.ID, r.AuthorID)
-	}()
-	t.relshipsSet = true
+	t.relshipsOnce.Do(func() {
+		func() {
+			r := newPosts(t.alias+"_posts", t.schema)
+			t.Posts = geq.NewRelship(t, r, t.ID, r.AuthorID)
+		}()
+	})
 }
 func (t *TableUsers) FieldPtrs(r *mdl.User) []any {
 	return []any{&r.ID, &r.Name}
 }
+func (t *TableUsers) PK() *geq.Column[uint64] {
+	return t.ID
+}
 func (t *TableUsers) As(alias string) *TableUsers {
-	return NewUsers(alias)
+	return newUsers(alias, t.schema)
+}
+func (t *TableUsers) WithSchema(schema string) *TableUsers {
+	return newUsers(t.alias, schema)
 }
 
 type TablePosts struct {
 	*geq.TableBase
-	relshipsSet bool
-	alias       string
-	ID          *geq.Column[uint64]
-	Title       *geq.Column[string]
-	AuthorID    *geq.Column[uint64]
-	Published   *geq.Column[bool]
-	Author      *geq.Relship[*TableUsers, mdl.User, uint64]
+	relshipsOnce sync.Once
+	alias        string
+	schema       string
+	ID           *geq.Column[uint64]
+	Title        *geq.Column[string]
+	AuthorID     *geq.Column[uint64]
+	Published    *geq.Column[bool]
+	Author       *geq.Relship[*TableUsers, mdl.User, uint64]
 }
 
 func NewPosts(alias string) *TablePosts {
+	return newPosts(alias, "")
+}
+
+func newPosts(alias, schema string) *TablePosts {
 	t := &TablePosts{
 		alias:     alias,
+		schema:    schema,
 		ID:        geq.NewColumn[uint64](alias, "id"),
 		Title:     geq.NewColumn[string](alias, "title"),
 		AuthorID:  geq.NewColumn[uint64](alias, "author_id"),
@@ -79,91 +98,121 @@ func NewPosts(alias string) *TablePosts {
 	}
 	columns := []geq.AnyColumn{t.ID, t.Title, t.AuthorID, t.Published}
 	sels := []geq.Selection{t.ID, t.Title, t.AuthorID, t.Published}
-	t.TableBase = geq.NewTableBase("posts", alias, columns, sels)
+	t.TableBase = geq.NewTableBase(schema, "posts", alias, columns, sels, geq.TableOptions{
+		PKs: []geq.AnyColumn{t.ID},
+	})
 	return t
 }
 
 func (t *TablePosts) InitRelships() {
-	if t.relshipsSet {
-		return
-	}
-	func() {
-		r := NewUsers(t.alias + "_users")
-		t.Author = geq.NewRelship(r, t.AuthorID, r.ID)
-	}()
-	t.relshipsSet = true
+	t.relshipsOnce.Do(func() {
+		func() {
+			r := newUsers(t.alias+"_author", t.schema)
+			t.Author = geq.NewRelship(t, r, t.AuthorID, r.ID)
+		}()
+	})
 }
 func (t *TablePosts) FieldPtrs(r *mdl.Post) []any {
 	return []any{&r.ID, &r.Title, &r.AuthorID, &r.Published}
 }
+func (t *TablePosts) PK() *geq.Column[uint64] {
+	return t.ID
+}
 func (t *TablePosts) As(alias string) *TablePosts {
-	return NewPosts(alias)
+	return newPosts(alias, t.schema)
+}
+func (t *TablePosts) WithSchema(schema string) *TablePosts {
+	return newPosts(t.alias, schema)
 }
 
 type TableCountries struct {
 	*geq.TableBase
-	relshipsSet bool
-	alias       string
-	ID          *geq.Column[uint32]
-	Name        *geq.Column[string]
+	relshipsOnce sync.Once
+	alias        string
+	schema       string
+	ID           *geq.Column[uint32]
+	Name         *geq.Column[string]
 }
 
 func NewCountries(alias string) *TableCountries {
+	return newCountries(alias, "")
+}
+
+func newCountries(alias, schema string) *TableCountries {
 	t := &TableCountries{
-		alias: alias,
-		ID:    geq.NewColumn[uint32](alias, "id"),
-		Name:  geq.NewColumn[string](alias, "name"),
+		alias:  alias,
+		schema: schema,
+		ID:     geq.NewColumn[uint32](alias, "id"),
+		Name:   geq.NewColumn[string](alias, "name"),
 	}
 	columns := []geq.AnyColumn{t.ID, t.Name}
 	sels := []geq.Selection{t.ID, t.Name}
-	t.TableBase = geq.NewTableBase("countries", alias, columns, sels)
+	t.TableBase = geq.NewTableBase(schema, "countries", alias, columns, sels, geq.TableOptions{
+		PKs: []geq.AnyColumn{t.ID},
+	})
 	return t
 }
 
 func (t *TableCountries) InitRelships() {
-	if t.relshipsSet {
-		return
-	}
-	t.relshipsSet = true
+	t.relshipsOnce.Do(func() {
+	})
 }
 func (t *TableCountries) FieldPtrs(r *mdl.Country) []any {
 	return []any{&r.ID, &r.Name}
 }
+func (t *TableCountries) PK() *geq.Column[uint32] {
+	return t.ID
+}
 func (t *TableCountries) As(alias string) *TableCountries {
-	return NewCountries(alias)
+	return newCountries(alias, t.schema)
+}
+func (t *TableCountries) WithSchema(schema string) *TableCountries {
+	return newCountries(t.alias, schema)
 }
 
 type TableCities struct {
 	*geq.TableBase
-	relshipsSet bool
-	alias       string
-	ID          *geq.Column[uint64]
-	Name        *geq.Column[string]
-	CountryID   *geq.Column[uint32]
+	relshipsOnce sync.Once
+	alias        string
+	schema       string
+	ID           *geq.Column[uint64]
+	Name         *geq.Column[string]
+	CountryID    *geq.Column[uint32]
 }
 
 func NewCities(alias string) *TableCities {
+	return newCities(alias, "")
+}
+
+func newCities(alias, schema string) *TableCities {
 	t := &TableCities{
 		alias:     alias,
+		schema:    schema,
 		ID:        geq.NewColumn[uint64](alias, "id"),
 		Name:      geq.NewColumn[string](alias, "name"),
 		CountryID: geq.NewColumn[uint32](alias, "country_id"),
 	}
 	columns := []geq.AnyColumn{t.ID, t.Name, t.CountryID}
 	sels := []geq.Selection{t.ID, t.Name, t.CountryID}
-	t.TableBase = geq.NewTableBase("cities", alias, columns, sels)
+	t.TableBase = geq.NewTableBase(schema, "cities", alias, columns, sels, geq.TableOptions{
+		PKs: []geq.AnyColumn{t.ID},
+	})
 	return t
 }
 
 func (t *TableCities) InitRelships() {
-	if t.relshipsSet {
-		return
-	}
-	t.relshipsSet = true
+	t.relshipsOnce.Do(func() {
+	})
 }
 func (t *TableCities) FieldPtrs(r *mdl.City) []any {
 	return []any{&r.ID, &r.Name, &r.CountryID}
 }
+func (t *TableCities) PK() *geq.Column[uint64] {
+	return t.ID
+}
 func (t *TableCities) As(alias string) *TableCities {
-	return NewCities(alias)
+	return newCities(alias, t.schema)
+}
+func (t *TableCities) WithSchema(schema string) *TableCities {
+	return newCities(t.alias, schema)
 }
@@ -66,6 +66,7 @@ func (a *aliased) getAlias() string { return a.alias }
 type AnyColumn interface {
 	Expr
 	getColumnName() string
+	fieldValue(ptr any) any
 }
 
 type Column[F any] struct {
@@ -86,6 +87,11 @@ func (c *Column[F]) getColumnName() string {
 	return c.columnName
 }
 
+// fieldValue returns the value of a field pointer returned by FieldPtrs.
+func (c *Column[F]) fieldValue(ptr any) any {
+	return *ptr.(*F)
+}
+
 func (c *Column[F]) appendExpr(w *queryWriter, cfg *QueryConfig) {
 	w.Printf("%s.%s", cfg.dialect.Ident(c.tableName), cfg.dialect.Ident(c.columnName))
 }
@@ -162,10 +168,11 @@ func (e *infixExpr) appendExpr(w *queryWriter, cfg *QueryConfig) {
 	// On the other hand, the left side expression does not be wrapped.
 	//   a.Add(b).Mlt(c) //=> a + b * c
 	// Because the code and the generated expression match, making it less likely to cause confusion.
-	if e.getPrecedence() > e.right.getPrecedence() {
-		e.right = Parens(e.right)
+	right := e.right
+	if e.getPrecedence() > right.getPrecedence() {
+		right = Parens(right)
 	}
-	e.right.appendExpr(w, cfg)
+	right.appendExpr(w, cfg)
 }
 
 type suffixExpr struct {
@@ -242,6 +249,94 @@ func (e *inExpr) appendExpr(w *queryWriter, cfg *QueryConfig) {
 	w.Write(")")
 }
 
+// inQueryExpr is an IN expression with a sub query, such as a IN (SELECT ...).
+type inQueryExpr struct {
+	ops
+	operand Expr
+	query   Expr
+}
+
+func (e *inQueryExpr) getPrecedence() int {
+	return prcdLowExpr
+}
+
+func (e *inQueryExpr) appendExpr(w *queryWriter, cfg *QueryConfig) {
+	e.operand.appendExpr(w, cfg)
+	w.Write(" IN ")
+	e.query.appendExpr(w, cfg)
+}
+
+type existsExpr struct {
+	ops
+	query Expr
+}
+
+func (e *existsExpr) getPrecedence() int {
+	return prcdValue
+}
+
+func (e *existsExpr) appendExpr(w *queryWriter, cfg *QueryConfig) {
+	w.Write("EXISTS")
+	e.query.appendExpr(w, cfg)
+}
+
+// rowExpr is a row value, such as (a, b).
+type rowExpr struct {
+	ops
+	exprs []Expr
+}
+
+func (e *rowExpr) getPrecedence() int {
+	return prcdValue
+}
+
+func (e *rowExpr) appendExpr(w *queryWriter, cfg *QueryConfig) {
+	w.Write("(")
+	for i, v := range e.exprs {
+		if i > 0 {
+			w.Write(", ")
+		}
+		v.appendExpr(w, cfg)
+	}
+	w.Write(")")
+}
+
+// rowInExpr is an IN expression for row values, such as (a, b) IN ((1, 2), (3, 4)).
+type rowInExpr struct {
+	ops
+	operands []Expr
+	rows     [][]any
+}
+
+func (e *rowInExpr) getPrecedence() int {
+	return prcdLowExpr
+}
+
+func (e *rowInExpr) appendExpr(w *queryWriter, cfg *QueryConfig) {
+	w.Write("(")
+	for i, o := range e.operands {
+		if i > 0 {
+			w.Write(", ")
+		}
+		o.appendExpr(w, cfg)
+	}
+	w.Write(") IN (")
+	for i, row := range e.rows {
+		if i > 0 {
+			w.Write(", ")
+		}
+		w.Write("(")
+		for j, v := range row {
+			if j > 0 {
+				w.Write(", ")
+			}
+			toExpr(v).appendExpr(w, cfg)
+		}
+		w.Write(")")
+	}
+	w.Write(")")
+}
+
 type FuncExpr struct {
 	ops
 	distinct bool
@@ -1,11 +1,50 @@
 package geq
 
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// ErrNoRows is returned when no record is found. It wraps sql.ErrNoRows.
+var ErrNoRows = fmt.Errorf("[geq] no rows: %w", sql.ErrNoRows)
+
+// ErrMultipleRows is returned when more than one record is found where at most one is expected.
+var ErrMultipleRows = errors.New("[geq] multiple rows")
+
+// ErrStaleRecord is returned when an update with a version check affects no rows,
+// because the record was updated or deleted by others.
+var ErrStaleRecord = errors.New("[geq] stale record")
+
 var defaultDialect Dialect = &DialectGeneric{}
 
 func SetDefaultDialect(d Dialect) {
 	defaultDialect = d
 }
 
+var clock atomic.Pointer[func() time.Time]
+
+// SetClock sets a function to get the current time, which is used for automatic timestamps
+// and soft delete. This is useful to freeze time in tests. A nil function restores time.Now.
+// It is safe to call concurrently with building queries.
+func SetClock(now func() time.Time) {
+	if now == nil {
+		clock.Store(nil)
+		return
+	}
+	clock.Store(&now)
+}
+
+func currentTime() time.Time {
+	if now := clock.Load(); now != nil {
+		return (*now)()
+	}
+	return time.Now()
+}
+
 func AsMap[R any, K comparable](key *Column[K], q *Query[R]) *MapLoader[R, R, K] {
 	return &MapLoader[R, R, K]{query: q, mapper: q.mapper, key: key}
 }
@@ -26,6 +65,14 @@ func ToSliceMap[R any, K comparable](mapper RowMapper[R], key TypedSelection[K],
 	return &SliceMapScanner[R, K]{mapper: mapper, dest: dest, key: key}
 }
 
+func ToSliceOptional[R any](mapper RowMapper[R], key Selection, dest *[]*R) *OptionalSliceScanner[R] {
+	return &OptionalSliceScanner[R]{mapper: mapper, dest: dest, key: key}
+}
+
+func ToMapOptional[R any, K comparable](mapper RowMapper[R], key TypedSelection[K], dest *map[K]R) *OptionalMapScanner[R, K] {
+	return &OptionalMapScanner[R, K]{mapper: mapper, dest: dest, key: key}
+}
+
 func SelectFrom[R any](table Table[R], sels ...Selection) *Query[R] {
 	q := newQuery(table).From(table)
 	if len(sels) > 0 {
@@ -50,10 +97,29 @@ func Select(sels ...Selection) *Query[struct{}] {
 	return q
 }
 
-func SelectVia[S, T, C any, RT Table[S]](srcs []S, table Table[T], relship *Relship[RT, S, C]) *Query[T] {
+// Pluck returns a query that selects only the given column using the conditions of the query.
+func Pluck[R, V any](q *Query[R], col *Column[V]) *Query[V] {
+	mapper := &ValueMapper[V]{sels: []Selection{col}}
+	return withMapper(q, mapper)
+}
+
+func SelectVia[S, T any](srcs []S, table Table[T], relship RelshipOf[S]) *Query[T] {
 	return newQuery(table).From(table).Where(relship.In(srcs))
 }
 
+// FindByPK loads a record by its primary key. It returns ErrNoRows if the record does not exist.
+func FindByPK[R any, K comparable](ctx context.Context, db QueryRunner, table TableWithPK[R, K], pk K) (R, error) {
+	return SelectFrom[R](table).Where(table.PK().Eq(pk)).LoadOne(ctx, db)
+}
+
+// FindAllByPKs loads records by their primary keys. Records that do not exist are not contained in the result.
+func FindAllByPKs[R any, K comparable](ctx context.Context, db QueryRunner, table TableWithPK[R, K], pks []K) (map[K]R, error) {
+	if len(pks) == 0 {
+		return map[K]R{}, nil
+	}
+	return AsMap(table.PK(), SelectFrom[R](table).Where(table.PK().In(pks))).Load(ctx, db)
+}
+
 func InsertInto(table AnyTable) *InsertQuery {
 	return newInsertQuery(table)
 }
@@ -70,6 +136,30 @@ func Null() AnonExpr {
 	return implOps(&nullExpr{})
 }
 
+// And joins the expressions by AND. Nil expressions are ignored and nil is returned if none remain,
+// which is ignored by Where as well.
+func And(exprs ...Expr) Expr {
+	return joinExprs("AND", prcdAnd, exprs)
+}
+
+// Or joins the expressions by OR. Nil expressions are ignored and nil is returned if none remain,
+// which is ignored by Where as well.
+func Or(exprs ...Expr) Expr {
+	return joinExprs("OR", prcdOr, exprs)
+}
+
+// AndIf returns the expression if cond is true, otherwise nil.
+func AndIf(cond bool, expr Expr) Expr {
+	if !cond {
+		return nil
+	}
+	return expr
+}
+
+func Exists(query Expr) AnonExpr {
+	return implOps(&existsExpr{query: query})
+}
+
 func Parens(expr Expr) AnonExpr {
 	return implOps(&parensExpr{expr: expr})
 }
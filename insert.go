@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"maps"
+	"slices"
 )
 
 type ValueMap map[AnyColumn]any
@@ -22,24 +24,33 @@ func newInsertQuery(table AnyTable) *InsertQuery {
 	return &InsertQuery{table: table}
 }
 
+// Clone returns a copy of the query that shares no mutable state with the original.
+func (q *InsertQuery) Clone() *InsertQuery {
+	c := *q
+	c.valueMaps = slices.Clone(q.valueMaps)
+	return &c
+}
+
 func (q *InsertQuery) Values(pairs ...ValuePair) *InsertQuery {
 	m := make(map[AnyColumn]Expr, len(pairs))
 	for _, p := range pairs {
 		m[p.column] = p.value
 	}
-	q.valueMaps = append(q.valueMaps, m)
-	return q
+	c := q.Clone()
+	c.valueMaps = append(c.valueMaps, m)
+	return c
 }
 
 func (q *InsertQuery) ValueMaps(vms ...ValueMap) *InsertQuery {
+	c := q.Clone()
 	for _, vm := range vms {
 		em := make(map[AnyColumn]Expr, len(vm))
 		for k, v := range vm {
 			em[k] = toExpr(v)
 		}
-		q.valueMaps = append(q.valueMaps, em)
+		c.valueMaps = append(c.valueMaps, em)
 	}
-	return q
+	return c
 }
 
 func (q *InsertQuery) Build() (bq *BuiltQuery, err error) {
@@ -49,20 +60,21 @@ func (q *InsertQuery) Build() (bq *BuiltQuery, err error) {
 
 func (q *InsertQuery) BuildWith(cfg *QueryConfig) (bq *BuiltQuery, err error) {
 	w := newQueryWriter()
-	w.Printf("INSERT INTO %s ", cfg.dialect.Ident(q.table.getTableName()))
+	w.Printf("INSERT INTO %s ", qualifiedTableName(q.table, cfg))
 
 	if len(q.valueMaps) == 0 {
 		return nil, errors.New("[geq.InsertInto] no values provided")
 	}
+	valueMaps := q.withAutoTimes()
 
-	valsLen := len(q.valueMaps[0])
+	valsLen := len(valueMaps[0])
 	if valsLen == 0 {
 		return nil, errors.New("[geq.InsertInto] values empty")
 	}
 
 	columns := make([]AnyColumn, 0, valsLen)
 	for _, c := range q.table.getColumns() {
-		_, ok := q.valueMaps[0][c]
+		_, ok := valueMaps[0][c]
 		if ok {
 			columns = append(columns, c)
 		}
@@ -76,11 +88,11 @@ func (q *InsertQuery) BuildWith(cfg *QueryConfig) (bq *BuiltQuery, err error) {
 		if i > 0 {
 			w.Write(", ")
 		}
-		w.Write(col.getColumnName())
+		w.Write(cfg.dialect.Ident(col.getColumnName()))
 	}
 	w.Write(") VALUES ")
 
-	for i, m := range q.valueMaps {
+	for i, m := range valueMaps {
 		if len(m) != valsLen {
 			return nil, errors.New("[geq.InsertInto] values length not match")
 		}
@@ -101,9 +113,37 @@ func (q *InsertQuery) BuildWith(cfg *QueryConfig) (bq *BuiltQuery, err error) {
 		w.Write(")")
 	}
 
+	if err := w.Err(); err != nil {
+		return nil, err
+	}
 	return &BuiltQuery{Query: w.String(), Args: w.Args}, nil
 }
 
+// withAutoTimes returns the value maps with the current time for the automatic timestamp columns not specified.
+func (q *InsertQuery) withAutoTimes() []map[AnyColumn]Expr {
+	var cols []AnyColumn
+	for _, c := range []AnyColumn{q.table.getAutoCreateTime(), q.table.getAutoUpdateTime()} {
+		if c != nil {
+			cols = append(cols, c)
+		}
+	}
+	if len(cols) == 0 {
+		return q.valueMaps
+	}
+	now := toExpr(currentTime())
+	valueMaps := make([]map[AnyColumn]Expr, 0, len(q.valueMaps))
+	for _, m := range q.valueMaps {
+		m = maps.Clone(m)
+		for _, c := range cols {
+			if _, ok := m[c]; !ok {
+				m[c] = now
+			}
+		}
+		valueMaps = append(valueMaps, m)
+	}
+	return valueMaps
+}
+
 func (q *InsertQuery) Exec(ctx context.Context, db QueryExecutor) (result sql.Result, err error) {
 	bq, err := q.Build()
 	if err != nil {
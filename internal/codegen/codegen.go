@@ -1,7 +1,11 @@
 package codegen
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"go/types"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -13,12 +17,132 @@ const geqPkgPath = "github.com/ryym/geq"
 
 type Config struct {
 	RootPath string
+
+	// OutDir overrides the output directory specified by the geq:outdir comment.
+	OutDir string
+
+	// FileName is the name of the generated file. The default is geq.gen.go.
+	FileName string
+
+	// Packages limits the target packages by import path patterns such as "example.com/app/..." if not empty.
+	Packages []string
+
+	// Log receives the progress of the generation if not nil.
+	Log io.Writer
 }
 
+// ErrOutdated is returned by Check when the generated files differ from the files on disk.
+var ErrOutdated = errors.New("generated files are not up to date")
+
+// Run generates the files and writes them to disk.
 func Run(cfg *Config) (err error) {
+	files, err := generate(cfg)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		dir, name := filepath.Split(f.path)
+		err = os.MkdirAll(dir, os.ModePerm)
+		if err != nil {
+			return fmt.Errorf("failed to create package directory: %w", err)
+		}
+		err = writeFile(dir, name, f.src)
+		if err != nil {
+			return err
+		}
+		cfg.logf("generated %s", f.path)
+	}
+	return nil
+}
+
+// Check generates the files in memory and writes unified diffs against the files on disk to w.
+// It returns ErrOutdated if any file differs, without changing the files.
+// Generated files which are no longer generated by any geqbld.go are also reported
+// unless the target packages are limited by Packages.
+func Check(cfg *Config, w io.Writer) (err error) {
+	files, err := generate(cfg)
+	if err != nil {
+		return err
+	}
+	outdated := false
+	for _, f := range files {
+		cur, err := os.ReadFile(f.path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to read %s: %w", f.path, err)
+		}
+		if bytes.Equal(cur, f.src) {
+			cfg.logf("up to date %s", f.path)
+			continue
+		}
+		outdated = true
+		_, err = io.WriteString(w, unifiedDiff(f.path, string(cur), string(f.src)))
+		if err != nil {
+			return err
+		}
+	}
+	if len(cfg.Packages) == 0 {
+		orphans, err := findOrphanFiles(cfg, files)
+		if err != nil {
+			return err
+		}
+		for _, path := range orphans {
+			outdated = true
+			_, err = fmt.Fprintf(w, "%s is not generated by any geqbld.go, remove it\n", path)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if outdated {
+		return ErrOutdated
+	}
+	return nil
+}
+
+type generatedFile struct {
+	path string
+	src  []byte
+}
+
+// findOrphanFiles finds the files generated by geq under the root path which are not in the given files.
+func findOrphanFiles(cfg *Config, files []*generatedFile) (orphans []string, err error) {
+	generated := make(map[string]struct{}, len(files))
+	for _, f := range files {
+		generated[f.path] = struct{}{}
+	}
+	err = filepath.Walk(cfg.RootPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if skipDir(cfg.RootPath, path, info) {
+			return filepath.SkipDir
+		}
+		if info.IsDir() || info.Name() != cfg.fileName() {
+			return nil
+		}
+		path, err = filepath.Abs(path)
+		if err != nil {
+			return err
+		}
+		if _, ok := generated[path]; ok {
+			return nil
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if bytes.HasPrefix(src, []byte(strings.TrimSpace(autoGenWarning))) {
+			orphans = append(orphans, path)
+		}
+		return nil
+	})
+	return orphans, err
+}
+
+func generate(cfg *Config) (files []*generatedFile, err error) {
 	cwd, err := os.Getwd()
 	if err != nil {
-		return fmt.Errorf("failed to get current working directory: %w", err)
+		return nil, fmt.Errorf("failed to get current working directory: %w", err)
 	}
 
 	bldPaths := make([]string, 0)
@@ -26,21 +150,59 @@ func Run(cfg *Config) (err error) {
 		if err != nil {
 			return err
 		}
+		if skipDir(cfg.RootPath, path, info) {
+			return filepath.SkipDir
+		}
 		if info.Name() == "geqbld.go" {
 			bldPaths = append(bldPaths, absPath(cwd, filepath.Dir(path)))
 		}
 		return nil
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = genBuildersFiles(bldPaths)
-	if err != nil {
-		return err
+	return genBuildersFiles(cfg, bldPaths)
+}
+
+// skipDir reports whether the directory should be skipped in the same way as the go tool,
+// which ignores testdata, vendor and directories beginning with "." or "_".
+func skipDir(root, path string, info os.FileInfo) bool {
+	if !info.IsDir() || path == root {
+		return false
 	}
+	name := info.Name()
+	return name == "testdata" || name == "vendor" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
 
-	return nil
+func (c *Config) fileName() string {
+	if c.FileName == "" {
+		return "geq.gen.go"
+	}
+	return c.FileName
+}
+
+func (c *Config) logf(format string, args ...any) {
+	if c.Log != nil {
+		fmt.Fprintf(c.Log, format+"\n", args...)
+	}
+}
+
+// matchPackage reports whether the package path matches the package filters.
+func (c *Config) matchPackage(pkgPath string) bool {
+	if len(c.Packages) == 0 {
+		return true
+	}
+	for _, p := range c.Packages {
+		if prefix, ok := strings.CutSuffix(p, "/..."); ok {
+			if pkgPath == prefix || strings.HasPrefix(pkgPath, prefix+"/") {
+				return true
+			}
+		} else if pkgPath == p {
+			return true
+		}
+	}
+	return false
 }
 
 type builderConfig struct {
@@ -57,73 +219,112 @@ type builderFileDef struct {
 }
 
 type tableDef struct {
-	Name     string
-	DbName   string
-	RowName  string
-	Fields   []tableFieldDef
-	Relships []*relshipDef
+	Name           string
+	DbName         string
+	Schema         string
+	RowName        string
+	Fields         []tableFieldDef
+	PKs            []tableFieldDef
+	Version        *tableFieldDef
+	SoftDelete     *tableFieldDef
+	AutoCreateTime *tableFieldDef
+	AutoUpdateTime *tableFieldDef
+	Relships       []*relshipDef
 }
 
 type tableFieldDef struct {
 	Name   string
 	DbName string
 	Type   string
+	typ    types.Type
+	pk     bool
+	role   string
 }
 
 type relshipDef struct {
-	MapperR   *tableDef
-	RowNameR  string
-	RelName   string
+	MapperR    *tableDef
+	SameSchema bool
+	RowNameR   string
+	RelName    string
+	Alias      string
+	FieldL     string
+	FieldR     string
+	FieldType  string
+	ExtraKeys  []relshipKeyDef
+	Filters    []relshipFilterDef
+	Via        *relshipViaDef
+}
+
+type relshipViaDef struct {
+	Mapper     *tableDef
+	SameSchema bool
+	FieldL     string
+	FieldR     string
+}
+
+type relshipKeyDef struct {
 	FieldL    string
 	FieldR    string
 	FieldType string
 }
 
-func genBuildersFiles(bldPaths []string) (err error) {
+type relshipFilterDef struct {
+	Left   bool
+	Field  string
+	Method string
+	Value  string
+}
+
+func genBuildersFiles(cfg *Config, bldPaths []string) (files []*generatedFile, err error) {
 	pkgCfg := &packages.Config{Mode: packages.NeedName | packages.NeedFiles | packages.NeedTypes | packages.NeedSyntax}
 	for _, bldPath := range bldPaths {
 		pkgs, err := loadPkgs(pkgCfg, bldPath)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		pkg := pkgs[0]
-		err = genBuilderFile(bldPath, pkg)
+		if !cfg.matchPackage(pkg.PkgPath) {
+			cfg.logf("skip %s", pkg.PkgPath)
+			continue
+		}
+		f, err := genBuilderFile(cfg, bldPath, pkg)
 		if err != nil {
-			return fmt.Errorf("builder generation failed at: %s: %w", pkg.ID, err)
+			return nil, fmt.Errorf("builder generation failed at: %s: %w", pkg.ID, err)
+		}
+		if f != nil {
+			files = append(files, f)
 		}
 	}
-	return nil
+	return files, nil
 }
 
-func genBuilderFile(rootPath string, pkg *packages.Package) (err error) {
-	cfg, err := parseBuilderConfig(pkg)
+// genBuilderFile returns the builder file of the package, or nil if the package defines nothing.
+func genBuilderFile(cfg *Config, rootPath string, pkg *packages.Package) (f *generatedFile, err error) {
+	bcfg, err := parseBuilderConfig(pkg)
 	if err != nil {
-		return err
+		return nil, err
 	}
-
-	def, err := buildBuilderFileDef(pkg, cfg)
-	if err != nil {
-		return err
+	if cfg.OutDir != "" {
+		bcfg, err = newBuilderConfig(pkg, cfg.OutDir)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	destDir := filepath.Join(rootPath, cfg.outdir)
-	err = os.MkdirAll(destDir, os.ModePerm)
+	def, err := buildBuilderFileDef(pkg, bcfg)
 	if err != nil {
-		return fmt.Errorf("failed to create package directory: %w", err)
+		return nil, err
 	}
-
-	if len(def.Tables) > 0 || len(def.RowMappers) > 0 {
-		src, err := buildGoCode("builderFile", builderFileTmpl, def)
-		if err != nil {
-			return err
-		}
-		err = writeFile(destDir, "geq.gen.go", src)
-		if err != nil {
-			return err
-		}
+	if len(def.Tables) == 0 && len(def.RowMappers) == 0 {
+		return nil, nil
 	}
 
-	return nil
+	src, err := buildGoCode("builderFile", builderFileTmpl, def)
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(rootPath, bcfg.outdir, cfg.fileName())
+	return &generatedFile{path: path, src: src}, nil
 }
 
 func buildBuilderFileDef(pkg *packages.Package, cfg *builderConfig) (def *builderFileDef, err error) {
@@ -133,6 +334,9 @@ func buildBuilderFileDef(pkg *packages.Package, cfg *builderConfig) (def *builde
 	if err != nil {
 		return nil, err
 	}
+	if len(tables) > 0 {
+		imports["sync"] = struct{}{}
+	}
 
 	relsMap, err := parseRelationships(pkg, cfg, tables)
 	if err != nil {
@@ -166,8 +370,12 @@ func parseBuilderConfig(pkg *packages.Package) (cfg *builderConfig, err error) {
 	if !ok {
 		outdir = "./d"
 	}
+	return newBuilderConfig(pkg, outdir)
+}
+
+func newBuilderConfig(pkg *packages.Package, outdir string) (cfg *builderConfig, err error) {
 	if strings.Contains(outdir, "..") {
-		return nil, fmt.Errorf("geq:outdir must not contain '..'")
+		return nil, fmt.Errorf("output directory must not contain '..'")
 	}
 
 	var pkgName string
@@ -209,46 +417,90 @@ func init() {
 {{range .Tables}}
 type Table{{.Name}} struct {
 	*geq.TableBase
-	relshipsSet bool
+	relshipsOnce sync.Once
 	alias string
+	schema string
 	{{range .Fields -}}
 	{{.Name}} *geq.Column[{{.Type}}]
 	{{end -}}
 	{{range .Relships -}}
+	{{if .Via -}}
+	{{.RelName}} *geq.ThroughRelship[*Table{{.MapperR.Name}}, {{.RowNameR}}, {{.FieldType}}]
+	{{else -}}
 	{{.RelName}} *geq.Relship[*Table{{.MapperR.Name}}, {{.RowNameR}}, {{.FieldType}}]
 	{{end -}}
+	{{end -}}
 }
 
 func New{{.Name}}(alias string) *Table{{.Name}} {
+	return new{{.Name}}(alias, "{{.Schema}}")
+}
+
+func new{{.Name}}(alias, schema string) *Table{{.Name}} {
 	t := &Table{{.Name}}{
 		alias: alias,
+		schema: schema,
 		{{range .Fields -}}
 		{{.Name}}: geq.NewColumn[{{.Type}}](alias, "{{.DbName}}"),
 		{{end -}}
 	}
 	columns := []geq.AnyColumn{ {{- range .Fields}} t.{{.Name}}, {{end -}} }
 	sels := []geq.Selection{ {{- range .Fields}} t.{{.Name}}, {{end -}} }
-	t.TableBase = geq.NewTableBase("{{.DbName}}", alias, columns, sels)
+	t.TableBase = geq.NewTableBase(schema, "{{.DbName}}", alias, columns, sels, geq.TableOptions{
+		PKs: []geq.AnyColumn{ {{- range .PKs}} t.{{.Name}}, {{end -}} },
+		{{- with .Version}}
+		Version: t.{{.Name}},
+		{{- end}}
+		{{- with .SoftDelete}}
+		SoftDelete: t.{{.Name}},
+		{{- end}}
+		{{- with .AutoCreateTime}}
+		AutoCreateTime: t.{{.Name}},
+		{{- end}}
+		{{- with .AutoUpdateTime}}
+		AutoUpdateTime: t.{{.Name}},
+		{{- end}}
+	})
 	return t
 }
 
 func (t *Table{{.Name}}) InitRelships()  {
-	if t.relshipsSet {
-		return
-	}
+	t.relshipsOnce.Do(func() {
 	{{range .Relships -}}
 	func() {
-		r := New{{.MapperR.Name}}(t.alias + "_{{.MapperR.DbName}}")
-		t.{{.RelName}} = geq.NewRelship(r, t.{{.FieldL}}, r.{{.FieldR}})
+		{{if .Via -}}
+		v := new{{.Via.Mapper.Name}}(t.alias + "_{{.Alias}}_{{.Via.Mapper.DbName}}", {{if .Via.SameSchema}}t.schema{{else}}"{{.Via.Mapper.Schema}}"{{end}})
+		{{end -}}
+		r := new{{.MapperR.Name}}(t.alias + "_{{.Alias}}", {{if .SameSchema}}t.schema{{else}}"{{.MapperR.Schema}}"{{end}})
+		{{if .Via -}}
+		t.{{.RelName}} = geq.NewThroughRelship(t, r, v, t.{{.FieldL}}, v.{{.Via.FieldL}}, v.{{.Via.FieldR}}, r.{{.FieldR}})
+		{{else -}}
+		t.{{.RelName}} = geq.NewRelship(t, r, t.{{.FieldL}}, r.{{.FieldR}},
+			{{- range .ExtraKeys}}
+			geq.RelKey(t.{{.FieldL}}, r.{{.FieldR}}),
+			{{- end}}
+			{{- range .Filters}}
+			{{if .Left}}geq.RelFilterL(t{{else}}geq.RelFilterR(r{{end}}.{{.Field}}.{{.Method}}({{.Value}})),
+			{{- end}}
+		)
+		{{end -}}
 	}()
 	{{end -}}
-	t.relshipsSet = true
+	})
 }
 func (t *Table{{.Name}}) FieldPtrs(r *{{.RowName}}) []any {
 	return []any{ {{- range .Fields}} &r.{{.Name}}, {{end -}} }
 }
+{{if eq (len .PKs) 1 -}}
+func (t *Table{{.Name}}) PK() *geq.Column[{{(index .PKs 0).Type}}] {
+	return t.{{(index .PKs 0).Name}}
+}
+{{end -}}
 func (t *Table{{.Name}}) As(alias string) *Table{{.Name}} {
-	return New{{.Name}}(alias)
+	return new{{.Name}}(alias, t.schema)
+}
+func (t *Table{{.Name}}) WithSchema(schema string) *Table{{.Name}} {
+	return new{{.Name}}(t.alias, schema)
 }
 
 {{end}}
@@ -1,13 +1,17 @@
 package codegen
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/go/packages"
 )
 
 func TestCodegenHelloworld(t *testing.T) {
@@ -60,3 +64,241 @@ func readFiles(paths []string) (m map[string]string, err error) {
 	}
 	return m, nil
 }
+
+func TestParseRelshipDef(t *testing.T) {
+	def := "Orders.TenantID = Items.TenantID AND Items.OrderID = Orders.ID AND Items.Note = 'a AND b' AND Orders.Canceled = false AND Items.DeletedAt IS NULL"
+	keys, filters, err := parseRelshipDef("Orders.Items", "Orders", "Items", def)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantKeys := []relshipKeyDef{
+		{FieldL: "TenantID", FieldR: "TenantID"},
+		{FieldL: "ID", FieldR: "OrderID"},
+	}
+	if diff := cmp.Diff(wantKeys, keys); diff != "" {
+		t.Error(diff)
+	}
+	wantFilters := []relshipFilterDef{
+		{Left: false, Field: "Note", Method: "Eq", Value: `"a AND b"`},
+		{Left: true, Field: "Canceled", Method: "Eq", Value: "false"},
+		{Left: false, Field: "DeletedAt", Method: "IsNull"},
+	}
+	if diff := cmp.Diff(wantFilters, filters); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestInvalidRelationships(t *testing.T) {
+	cases := []struct {
+		pkg  string
+		want string
+	}{
+		{
+			pkg:  "malformed",
+			want: `geqbld.go:19:3: relationship definition of Posts.Author is invalid: unsupported condition "Posts.AuthorID"`,
+		},
+		{
+			pkg:  "unknownfield",
+			want: `geqbld.go:19:3: relationship definition of Posts.Author is invalid: field Posts.WriterID not found`,
+		},
+		{
+			pkg: "typemismatch",
+			want: sjoin(
+				`geqbld.go:21:3: relationship definition of Posts.Author is invalid:`,
+				`types of Posts.AuthorID and Users.ID mismatch (int64, github.com/ryym/geq/internal/codegen/testdata/invalidrels/typemismatch.UserID)`,
+			),
+		},
+		{
+			pkg: "filtermismatch",
+			want: sjoin(
+				`geqbld.go:23:3: relationship definition of Users.Drafts is invalid:`,
+				`value "draft" mismatches type of Posts.Status (github.com/ryym/geq/internal/codegen/testdata/invalidrels/filtermismatch.PostStatus)`,
+			),
+		},
+	}
+
+	pkgCfg := &packages.Config{Mode: packages.NeedName | packages.NeedFiles | packages.NeedTypes | packages.NeedSyntax}
+	for _, c := range cases {
+		pkgs, err := loadPkgs(pkgCfg, "./testdata/invalidrels/"+c.pkg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := parseBuilderConfig(pkgs[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = buildBuilderFileDef(pkgs[0], cfg)
+		if err == nil {
+			t.Errorf("%s: no error", c.pkg)
+			continue
+		}
+		if !strings.HasSuffix(err.Error(), c.want) {
+			t.Errorf("%s: unexpected error:\n got: %s\nwant: %s", c.pkg, err, c.want)
+		}
+	}
+}
+
+func TestInvalidTables(t *testing.T) {
+	cases := []struct {
+		pkg  string
+		want string
+	}{
+		{
+			pkg:  "softdeletetime",
+			want: `Document invalid: softDelete field DeletedAt must be sql.NullTime`,
+		},
+		{
+			pkg:  "softdeleteint",
+			want: `Document invalid: softDelete field DeletedAt must be sql.NullTime`,
+		},
+		{
+			pkg:  "autotimestring",
+			want: `Note invalid: autoCreateTime field CreatedAt must be time.Time or sql.NullTime`,
+		},
+		{
+			pkg:  "autotimestruct",
+			want: `Note invalid: autoUpdateTime field UpdatedAt must be time.Time or sql.NullTime`,
+		},
+	}
+
+	pkgCfg := &packages.Config{Mode: packages.NeedName | packages.NeedFiles | packages.NeedTypes | packages.NeedSyntax}
+	for _, c := range cases {
+		pkgs, err := loadPkgs(pkgCfg, "./testdata/invalidtables/"+c.pkg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := parseBuilderConfig(pkgs[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = buildBuilderFileDef(pkgs[0], cfg)
+		if err == nil {
+			t.Errorf("%s: no error", c.pkg)
+			continue
+		}
+		if !strings.HasSuffix(err.Error(), c.want) {
+			t.Errorf("%s: unexpected error:\n got: %s\nwant: %s", c.pkg, err, c.want)
+		}
+	}
+}
+
+func sjoin(ss ...string) string {
+	return strings.Join(ss, " ")
+}
+
+func TestParseSelfRelshipDef(t *testing.T) {
+	keys, _, err := parseRelshipDef("Employees.Manager", "Employees", "Employees", "Employees.ManagerID = Employees.ID")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]relshipKeyDef{{FieldL: "ManagerID", FieldR: "ID"}}, keys); diff != "" {
+		t.Error(diff)
+	}
+
+	_, _, err = parseRelshipDef("Employees.Manager", "Employees", "Employees", "Employees.ManagerID = Employees.ID AND Employees.Retired = false")
+	if err == nil {
+		t.Error("fixed condition in self-referential relationship must be rejected")
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15\n"
+	b := "1\n2\n3\nfour\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15\n16\n"
+	got := unifiedDiff("a.go", a, b)
+	want := strings.Join([]string{
+		"--- a.go",
+		"+++ a.go (generated)",
+		"@@ -1,7 +1,7 @@",
+		" 1", " 2", " 3", "-4", "+four", " 5", " 6", " 7",
+		"@@ -13,3 +13,4 @@",
+		" 13", " 14", " 15", "+16",
+		"",
+	}, "\n")
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error(diff)
+	}
+
+	got = unifiedDiff("new.go", "", "x\ny\n")
+	want = "--- new.go\n+++ new.go (generated)\n@@ -0,0 +1,2 @@\n+x\n+y\n"
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error(diff)
+	}
+
+	got = unifiedDiff("eof.go", "x\ny", "x\ny\n")
+	want = "--- eof.go\n+++ eof.go (generated)\n@@ -1,2 +1,2 @@\n x\n-y\n\\ No newline at end of file\n+y\n"
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestCheckHelloworld(t *testing.T) {
+	pkgPath, err := filepath.Abs("../../examples/helloworld")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out strings.Builder
+	err = Check(&Config{RootPath: pkgPath}, &out)
+	if err != nil {
+		t.Fatalf("generated code must be up to date: %v\n%s", err, out.String())
+	}
+
+	// Files which do not exist are reported as outdated without being created.
+	out.Reset()
+	err = Check(&Config{RootPath: pkgPath, FileName: "missing.gen.go"}, &out)
+	if !errors.Is(err, ErrOutdated) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "+++ "+filepath.Join(pkgPath, "gen/d/missing.gen.go")+" (generated)") {
+		t.Errorf("unexpected diff:\n%s", out.String())
+	}
+	matches, err := filepath.Glob(filepath.Join(pkgPath, "*/*/missing.gen.go"))
+	if err != nil || len(matches) > 0 {
+		t.Errorf("files must not be written: %v %v", matches, err)
+	}
+}
+
+func TestCheckOrphanFiles(t *testing.T) {
+	dir := t.TempDir()
+	orphan := filepath.Join(dir, "d", "geq.gen.go")
+	err := os.MkdirAll(filepath.Dir(orphan), os.ModePerm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(orphan, []byte(strings.TrimSpace(autoGenWarning)+"\n\npackage d\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(dir, "geq.gen.go"), []byte("package handwritten\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Directories ignored by the go tool are not checked.
+	for _, ignored := range []string{"testdata", "vendor", ".git", "_old"} {
+		path := filepath.Join(dir, ignored, "geq.gen.go")
+		err = os.MkdirAll(filepath.Dir(path), os.ModePerm)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(path, []byte(strings.TrimSpace(autoGenWarning)+"\n\npackage d\n"), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out strings.Builder
+	err = Check(&Config{RootPath: dir}, &out)
+	if !errors.Is(err, ErrOutdated) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := orphan + " is not generated by any geqbld.go, remove it\n"
+	if diff := cmp.Diff(want, out.String()); diff != "" {
+		t.Error(diff)
+	}
+
+	// Files of the packages excluded by the filters are not checked.
+	err = Check(&Config{RootPath: dir, Packages: []string{"example.com/..."}}, io.Discard)
+	if err != nil {
+		t.Error(err)
+	}
+}
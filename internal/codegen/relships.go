@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"go/types"
 	"reflect"
+	"strconv"
 	"strings"
 
 	"golang.org/x/tools/go/packages"
@@ -20,17 +21,23 @@ func parseRelationships(pkg *packages.Package, cfg *builderConfig, tables []tabl
 	}
 
 	mapperMap := make(map[string]*tableDef, len(tables))
+	tableMap := make(map[string]*tableDef, len(tables))
 	for i := range tables {
 		mapperMap[tables[i].RowName] = &tables[i]
+		tableMap[tables[i].Name] = &tables[i]
 	}
 
 	relsMap = make(map[string][]*relshipDef, 0)
 	for i := 0; i < relStruct.NumFields(); i++ {
 		field := relStruct.Field(i)
 		mapperLName := field.Name()
+		mapperL, ok := tableMap[mapperLName]
+		if !ok {
+			return nil, errorAt(pkg, field.Pos(), fmt.Errorf("table of GeqRelationships field %s not found in GeqTables", mapperLName))
+		}
 		fieldStruct, ok := field.Type().(*types.Struct)
 		if !ok {
-			return nil, fmt.Errorf("type of field of GeqRelationships %s must be unnamed struct", mapperLName)
+			return nil, errorAt(pkg, field.Pos(), fmt.Errorf("type of field of GeqRelationships %s must be unnamed struct", mapperLName))
 		}
 		for j := 0; j < fieldStruct.NumFields(); j++ {
 			f := fieldStruct.Field(j)
@@ -39,7 +46,7 @@ func parseRelationships(pkg *packages.Package, cfg *builderConfig, tables []tabl
 
 			ft, ok := f.Type().(*types.Named)
 			if !ok {
-				return nil, fmt.Errorf("type of field of GeqRelationships %s must be named struct", fullRelName)
+				return nil, errorAt(pkg, f.Pos(), fmt.Errorf("type of field of GeqRelationships %s must be named struct", fullRelName))
 			}
 
 			var rowType string
@@ -51,76 +58,318 @@ func parseRelationships(pkg *packages.Package, cfg *builderConfig, tables []tabl
 			}
 			mapperR, ok := mapperMap[rowType]
 			if !ok {
-				return nil, fmt.Errorf("row type of relationship of %s is invalid: %s", fullRelName, rowType)
+				return nil, errorAt(pkg, f.Pos(), fmt.Errorf("row type of relationship of %s is not in GeqTables: %s", fullRelName, rowType))
 			}
 
-			fieldL, fieldR, err := parseRelshipStructTag(fullRelName, mapperLName, mapperR.Name, fieldStruct.Tag(j))
-			if err != nil {
-				return nil, err
+			relDef := reflect.StructTag(fieldStruct.Tag(j)).Get("geq")
+			if relDef == "" {
+				return nil, errorAt(pkg, f.Pos(), fmt.Errorf("relationship of %s must be defined in tag", fullRelName))
 			}
 
-			rs := &relshipDef{
-				MapperR:  mapperR,
-				RowNameR: mapperR.RowName,
-				RelName:  relName,
-				FieldL:   fieldL,
-				FieldR:   fieldR,
+			var rs *relshipDef
+			if hops := splitOutsideQuotes(relDef, ","); len(hops) > 1 {
+				rs, err = parseThroughRelship(fullRelName, mapperL, mapperR, tableMap, hops)
+			} else {
+				rs, err = parseDirectRelship(fullRelName, mapperL, mapperR, relDef)
+			}
+			if err != nil {
+				return nil, errorAt(pkg, f.Pos(), err)
 			}
+			rs.MapperR = mapperR
+			rs.SameSchema = mapperL.Schema == mapperR.Schema
+			rs.RowNameR = mapperR.RowName
+			rs.RelName = relName
+			rs.Alias = toSnake(relName)
 			relsMap[mapperLName] = append(relsMap[mapperLName], rs)
+		}
+	}
 
-			var ftL, ftR string
-			for _, t := range tables {
-				switch t.Name {
-				case mapperLName:
-					for _, f := range t.Fields {
-						if f.Name == rs.FieldL {
-							ftL = f.Type
-						}
-					}
-				case rs.MapperR.Name:
-					for _, f := range t.Fields {
-						if f.Name == rs.FieldR {
-							ftR = f.Type
-						}
-					}
-				}
-			}
-			if ftL != ftR {
-				return nil, fmt.Errorf("relationship field types of %s is invalid (%s, %s)", fullRelName, ftL, ftR)
+	return relsMap, nil
+}
+
+func parseDirectRelship(fullRelName string, mapperL, mapperR *tableDef, relDef string) (rs *relshipDef, err error) {
+	keys, filters, err := parseRelshipDef(fullRelName, mapperL.Name, mapperR.Name, relDef)
+	if err != nil {
+		return nil, err
+	}
+	for k := range keys {
+		keys[k].FieldType, err = relshipKeyType(fullRelName, mapperL, mapperR, keys[k])
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, f := range filters {
+		table := mapperR
+		if f.Left {
+			table = mapperL
+		}
+		field := lookupField(table, f.Field)
+		if field == nil {
+			return nil, fmt.Errorf("relationship definition of %s is invalid: field %s.%s not found", fullRelName, table.Name, f.Field)
+		}
+		if f.Value != "" && !relshipLiteralAssignable(f.Value, field.typ) {
+			return nil, fmt.Errorf(
+				"relationship definition of %s is invalid: value %s mismatches type of %s.%s (%s)",
+				fullRelName, f.Value, table.Name, f.Field, field.typ,
+			)
+		}
+	}
+	rs = &relshipDef{
+		FieldL:    keys[0].FieldL,
+		FieldR:    keys[0].FieldR,
+		FieldType: keys[0].FieldType,
+		ExtraKeys: keys[1:],
+		Filters:   filters,
+	}
+	return rs, nil
+}
+
+// parseThroughRelship parses a many-to-many relationship via a join table such as:
+//
+//	Users.ID = UserGroups.UserID, UserGroups.GroupID = Groups.ID
+func parseThroughRelship(fullRelName string, mapperL, mapperR *tableDef, tableMap map[string]*tableDef, hops []string) (rs *relshipDef, err error) {
+	if len(hops) != 2 {
+		return nil, fmt.Errorf("relationship definition of %s is invalid: only one join table is allowed", fullRelName)
+	}
+
+	var via *tableDef
+	for _, side := range strings.Split(hops[0], "=") {
+		tableName, _, _ := strings.Cut(strings.TrimSpace(side), ".")
+		if tableName != mapperL.Name {
+			via = tableMap[tableName]
+			if via == nil {
+				return nil, fmt.Errorf("relationship definition of %s is invalid: join table %s not found in GeqTables", fullRelName, tableName)
 			}
-			rs.FieldType = ftL
 		}
 	}
+	if via == nil {
+		return nil, fmt.Errorf("relationship definition of %s is invalid: no join table", fullRelName)
+	}
 
-	return relsMap, nil
+	keysL, filtersL, err := parseRelshipDef(fullRelName, mapperL.Name, via.Name, hops[0])
+	if err != nil {
+		return nil, err
+	}
+	keysR, filtersR, err := parseRelshipDef(fullRelName, via.Name, mapperR.Name, hops[1])
+	if err != nil {
+		return nil, err
+	}
+	if len(keysL) > 1 || len(keysR) > 1 || len(filtersL) > 0 || len(filtersR) > 0 {
+		return nil, fmt.Errorf("relationship definition of %s is invalid: each step of join table relationship must be a single column pair", fullRelName)
+	}
+
+	typeL, err := relshipKeyType(fullRelName, mapperL, via, keysL[0])
+	if err != nil {
+		return nil, err
+	}
+	_, err = relshipKeyType(fullRelName, via, mapperR, keysR[0])
+	if err != nil {
+		return nil, err
+	}
+
+	rs = &relshipDef{
+		FieldL:    keysL[0].FieldL,
+		FieldR:    keysR[0].FieldR,
+		FieldType: typeL,
+		Via: &relshipViaDef{
+			Mapper:     via,
+			SameSchema: mapperL.Schema == via.Schema,
+			FieldL:     keysL[0].FieldR,
+			FieldR:     keysR[0].FieldL,
+		},
+	}
+	return rs, nil
+}
+
+func relshipKeyType(fullRelName string, mapperL, mapperR *tableDef, key relshipKeyDef) (string, error) {
+	fL := lookupField(mapperL, key.FieldL)
+	if fL == nil {
+		return "", fmt.Errorf("relationship definition of %s is invalid: field %s.%s not found", fullRelName, mapperL.Name, key.FieldL)
+	}
+	fR := lookupField(mapperR, key.FieldR)
+	if fR == nil {
+		return "", fmt.Errorf("relationship definition of %s is invalid: field %s.%s not found", fullRelName, mapperR.Name, key.FieldR)
+	}
+	if !types.Identical(fL.typ, fR.typ) {
+		return "", fmt.Errorf(
+			"relationship definition of %s is invalid: types of %s.%s and %s.%s mismatch (%s, %s)",
+			fullRelName, mapperL.Name, key.FieldL, mapperR.Name, key.FieldR, fL.typ, fR.typ,
+		)
+	}
+	return fL.Type, nil
 }
 
-func parseRelshipStructTag(fullRelName, mapperLName, mapperRName, tagStr string) (fieldL string, fieldR string, err error) {
-	tag := reflect.StructTag(tagStr)
-	relDef := tag.Get("geq")
-	if relDef == "" {
-		err = fmt.Errorf("relationship of %s must be defined in tag", fullRelName)
-		return
+func lookupField(table *tableDef, fieldName string) *tableFieldDef {
+	for i, f := range table.Fields {
+		if f.Name == fieldName {
+			return &table.Fields[i]
+		}
 	}
+	return nil
+}
+
+// parseRelshipDef parses a relationship definition such as:
+//
+//	Orders.TenantID = Items.TenantID AND Orders.ID = Items.OrderID AND Items.DeletedAt IS NULL
+//
+// Each condition is either a column pair of the both tables or a fixed condition on one table.
+// In a self-referential relationship, the left operand of a column pair belongs to the left side table.
+func parseRelshipDef(fullRelName, mapperLName, mapperRName, relDef string) (keys []relshipKeyDef, filters []relshipFilterDef, err error) {
+	for _, cond := range splitOutsideQuotes(relDef, " AND ") {
+		cond = strings.TrimSpace(cond)
+		if lhs, ok := strings.CutSuffix(cond, " IS NULL"); ok {
+			f, err := parseRelshipFilterField(fullRelName, mapperLName, mapperRName, lhs)
+			if err != nil {
+				return nil, nil, err
+			}
+			f.Method = "IsNull"
+			filters = append(filters, *f)
+			continue
+		}
+		if lhs, ok := strings.CutSuffix(cond, " IS NOT NULL"); ok {
+			f, err := parseRelshipFilterField(fullRelName, mapperLName, mapperRName, lhs)
+			if err != nil {
+				return nil, nil, err
+			}
+			f.Method = "IsNotNull"
+			filters = append(filters, *f)
+			continue
+		}
+
+		method := "Eq"
+		lhs, rhs, ok := strings.Cut(cond, "<>")
+		if ok {
+			method = "Neq"
+		} else {
+			lhs, rhs, ok = strings.Cut(cond, "=")
+			if !ok {
+				return nil, nil, fmt.Errorf("relationship definition of %s is invalid: unsupported condition %q", fullRelName, cond)
+			}
+		}
+		lhs, rhs = strings.TrimSpace(lhs), strings.TrimSpace(rhs)
+
+		if value, ok := parseRelshipLiteral(rhs); ok {
+			f, err := parseRelshipFilterField(fullRelName, mapperLName, mapperRName, lhs)
+			if err != nil {
+				return nil, nil, err
+			}
+			f.Method = method
+			f.Value = value
+			filters = append(filters, *f)
+			continue
+		}
 
-	relParts := strings.SplitN(relDef, "=", 2)
-	fParts1 := strings.SplitN(strings.TrimSpace(relParts[0]), ".", 2)
-	fParts2 := strings.SplitN(strings.TrimSpace(relParts[1]), ".", 2)
+		if method != "Eq" {
+			return nil, nil, fmt.Errorf("relationship definition of %s is invalid: columns must be joined by '=': %s", fullRelName, cond)
+		}
+		table1, field1, err := splitFieldRef(fullRelName, lhs)
+		if err != nil {
+			return nil, nil, err
+		}
+		table2, field2, err := splitFieldRef(fullRelName, rhs)
+		if err != nil {
+			return nil, nil, err
+		}
 
-	var fPartsL, fPartsR []string
-	switch mapperLName {
-	case fParts1[0]:
-		fPartsL, fPartsR = fParts1, fParts2
-	case fParts2[0]:
-		fPartsL, fPartsR = fParts2, fParts1
+		var fieldL, fieldR string
+		switch {
+		case table1 == mapperLName && table2 == mapperRName:
+			fieldL, fieldR = field1, field2
+		case table2 == mapperLName && table1 == mapperRName:
+			fieldL, fieldR = field2, field1
+		default:
+			return nil, nil, fmt.Errorf(
+				"relationship definition of %s is invalid: %s must compare columns of %s and %s",
+				fullRelName, cond, mapperLName, mapperRName,
+			)
+		}
+		keys = append(keys, relshipKeyDef{FieldL: fieldL, FieldR: fieldR})
+	}
+
+	if len(keys) == 0 {
+		return nil, nil, fmt.Errorf("relationship definition of %s is invalid: no column pair", fullRelName)
+	}
+	return keys, filters, nil
+}
+
+// splitOutsideQuotes splits s by sep, ignoring ones in quoted strings.
+func splitOutsideQuotes(s, sep string) []string {
+	parts := make([]string, 0)
+	inQuote := false
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\'':
+			inQuote = !inQuote
+		case !inQuote && strings.HasPrefix(s[i:], sep):
+			parts = append(parts, s[start:i])
+			start = i + len(sep)
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func parseRelshipFilterField(fullRelName, mapperLName, mapperRName, field string) (f *relshipFilterDef, err error) {
+	tableName, fieldName, err := splitFieldRef(fullRelName, field)
+	if err != nil {
+		return nil, err
+	}
+	if mapperLName == mapperRName {
+		return nil, fmt.Errorf("relationship definition of %s is invalid: fixed condition on %s is ambiguous in self-referential relationship", fullRelName, tableName)
+	}
+	switch tableName {
+	case mapperLName:
+		return &relshipFilterDef{Left: true, Field: fieldName}, nil
+	case mapperRName:
+		return &relshipFilterDef{Left: false, Field: fieldName}, nil
 	default:
-		err = fmt.Errorf("relationship definition of %s is invalid: no %s", fullRelName, mapperLName)
-		return
+		return nil, fmt.Errorf("relationship definition of %s is invalid: unknown table %s", fullRelName, tableName)
 	}
-	if fPartsR[0] != mapperRName {
-		err = fmt.Errorf("relationship definition of %s is invalid: no %s", fullRelName, mapperRName)
-		return
+}
+
+func splitFieldRef(fullRelName, ref string) (tableName, fieldName string, err error) {
+	tableName, fieldName, ok := strings.Cut(strings.TrimSpace(ref), ".")
+	if !ok || tableName == "" || fieldName == "" {
+		return "", "", fmt.Errorf("relationship definition of %s is invalid: %q must be in the form of Table.Field", fullRelName, ref)
 	}
+	return tableName, fieldName, nil
+}
 
-	return fPartsL[1], fPartsR[1], nil
+// parseRelshipLiteral converts an SQL literal into Go code.
+func parseRelshipLiteral(v string) (goCode string, ok bool) {
+	switch {
+	case len(v) >= 2 && strings.HasPrefix(v, "'") && strings.HasSuffix(v, "'"):
+		return strconv.Quote(strings.ReplaceAll(v[1:len(v)-1], "''", "'")), true
+	case v == "TRUE" || v == "true":
+		return "true", true
+	case v == "FALSE" || v == "false":
+		return "false", true
+	}
+	if _, err := strconv.ParseFloat(v, 64); err == nil {
+		return v, true
+	}
+	return "", false
+}
+
+// relshipLiteralAssignable reports whether the literal converted by parseRelshipLiteral
+// can be compared with a field of the given type.
+func relshipLiteralAssignable(goCode string, typ types.Type) bool {
+	if ptr, ok := typ.Underlying().(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+	basic, ok := typ.Underlying().(*types.Basic)
+	if !ok {
+		return false
+	}
+	info := basic.Info()
+	switch {
+	case strings.HasPrefix(goCode, `"`):
+		return info&types.IsString != 0
+	case goCode == "true" || goCode == "false":
+		return info&types.IsBoolean != 0
+	case strings.ContainsAny(goCode, ".eE"):
+		return info&types.IsFloat != 0
+	default:
+		return info&(types.IsInteger|types.IsFloat) != 0
+	}
 }
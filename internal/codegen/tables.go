@@ -17,6 +17,11 @@ func parseTables(pkg *packages.Package, cfg *builderConfig, imports map[string]s
 		return nil, err
 	}
 
+	known := knownTypes{
+		time:     lookupImportedType(pkg.Types, "time", "Time"),
+		nullTime: lookupImportedType(pkg.Types, "database/sql", "NullTime"),
+	}
+
 	nTables := tablesStruct.NumFields()
 	tables = make([]tableDef, 0, nTables)
 	for i := 0; i < nTables; i++ {
@@ -46,20 +51,51 @@ func parseTables(pkg *packages.Package, cfg *builderConfig, imports map[string]s
 		}
 
 		tableFields := make([]tableFieldDef, 0, nTableFields)
+		var pks []tableFieldDef
+		roleFields := make(map[string]*tableFieldDef)
 		for j := 0; j < nTableFields; j++ {
 			f := fieldStruct.Field(j)
-			tfd, err := parseTableField(f, cfg, imports)
+			tfd, err := parseTableField(f, fieldStruct.Tag(j), cfg, known, imports)
 			if err != nil {
 				return nil, fmt.Errorf("table row %s invalid: %w", rowName, err)
 			}
+			if tfd.pk {
+				pks = append(pks, *tfd)
+			}
+			if tfd.role != "" {
+				if _, ok := roleFields[tfd.role]; ok {
+					return nil, fmt.Errorf("table row %s invalid: multiple %s fields", rowName, tfd.role)
+				}
+				roleFields[tfd.role] = tfd
+			}
 			tableFields = append(tableFields, *tfd)
 		}
+		if len(pks) == 0 {
+			// Use the ID field as a primary key by convention.
+			for j := range tableFields {
+				if tableFields[j].Name == "ID" {
+					tableFields[j].pk = true
+					pks = append(pks, tableFields[j])
+				}
+			}
+		}
+
+		opts, err := parseTagOptions(tablesStruct.Tag(i), []string{"schema"})
+		if err != nil {
+			return nil, fmt.Errorf("GeqTables field %s invalid: %w", mapperName, err)
+		}
 
 		td := tableDef{
-			Name:    mapperName,
-			DbName:  toSnake(mapperName),
-			RowName: rowName,
-			Fields:  tableFields,
+			Name:           mapperName,
+			DbName:         toSnake(mapperName),
+			Schema:         opts["schema"],
+			RowName:        rowName,
+			Fields:         tableFields,
+			PKs:            pks,
+			Version:        roleFields["version"],
+			SoftDelete:     roleFields["softDelete"],
+			AutoCreateTime: roleFields["autoCreateTime"],
+			AutoUpdateTime: roleFields["autoUpdateTime"],
 		}
 		tables = append(tables, td)
 	}
@@ -67,7 +103,52 @@ func parseTables(pkg *packages.Package, cfg *builderConfig, imports map[string]s
 	return tables, nil
 }
 
-func parseTableField(f *types.Var, cfg *builderConfig, imports map[string]struct{}) (tfd *tableFieldDef, err error) {
+// knownTypes holds the types used to validate fields. A type is nil if it is not imported.
+type knownTypes struct {
+	time     types.Type
+	nullTime types.Type
+}
+
+// is reports whether typ is identical to the known type.
+func (k knownTypes) is(typ, known types.Type) bool {
+	return known != nil && types.Identical(typ, known)
+}
+
+// fieldRoles are tag options which give fields special roles. Each role can be given to one field per table.
+var fieldRoles = []string{"version", "softDelete", "autoCreateTime", "autoUpdateTime"}
+
+func parseTableField(f *types.Var, tag string, cfg *builderConfig, known knownTypes, imports map[string]struct{}) (tfd *tableFieldDef, err error) {
+	opts, err := parseTagOptions(tag, append([]string{"pk"}, fieldRoles...))
+	if err != nil {
+		return nil, fmt.Errorf("field %s invalid: %w", f.Name(), err)
+	}
+	_, isPK := opts["pk"]
+	var role string
+	for _, r := range fieldRoles {
+		if _, ok := opts[r]; !ok {
+			continue
+		}
+		if role != "" {
+			return nil, fmt.Errorf("field %s invalid: both %s and %s specified", f.Name(), role, r)
+		}
+		role = r
+	}
+	basic, isBasic := f.Type().Underlying().(*types.Basic)
+	switch role {
+	case "version":
+		if !isBasic || basic.Info()&types.IsInteger == 0 {
+			return nil, fmt.Errorf("version field %s must be integer", f.Name())
+		}
+	case "softDelete":
+		// The field must be nullable since soft deleted rows are distinguished by IS NULL.
+		if !known.is(f.Type(), known.nullTime) {
+			return nil, fmt.Errorf("softDelete field %s must be sql.NullTime", f.Name())
+		}
+	case "autoCreateTime", "autoUpdateTime":
+		if !known.is(f.Type(), known.time) && !known.is(f.Type(), known.nullTime) {
+			return nil, fmt.Errorf("%s field %s must be time.Time or sql.NullTime", role, f.Name())
+		}
+	}
 	var typeName string
 	switch ft := f.Type().(type) {
 	case *types.Basic:
@@ -87,5 +168,8 @@ func parseTableField(f *types.Var, cfg *builderConfig, imports map[string]struct
 		Name:   f.Name(),
 		DbName: toSnake(f.Name()),
 		Type:   typeName,
+		typ:    f.Type(),
+		pk:     isPK,
+		role:   role,
 	}, nil
 }
@@ -0,0 +1,25 @@
+package filtermismatch
+
+type PostStatus int64
+
+type User struct {
+	ID int64
+}
+
+type Post struct {
+	ID       int64
+	AuthorID int64
+	Status   PostStatus
+}
+
+type GeqTables struct {
+	Users User
+	Posts Post
+}
+
+type GeqRelationships struct {
+	Users struct {
+		Posts  Post `geq:"Users.ID = Posts.AuthorID AND Posts.Status = 1"`
+		Drafts Post `geq:"Users.ID = Posts.AuthorID AND Posts.Status = 'draft'"`
+	}
+}
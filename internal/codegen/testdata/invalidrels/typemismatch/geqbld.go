@@ -0,0 +1,23 @@
+package typemismatch
+
+type UserID int64
+
+type User struct {
+	ID UserID
+}
+
+type Post struct {
+	ID       int64
+	AuthorID int64
+}
+
+type GeqTables struct {
+	Users User
+	Posts Post
+}
+
+type GeqRelationships struct {
+	Posts struct {
+		Author User `geq:"Posts.AuthorID = Users.ID"`
+	}
+}
@@ -0,0 +1,21 @@
+package unknownfield
+
+type User struct {
+	ID int64
+}
+
+type Post struct {
+	ID       int64
+	AuthorID int64
+}
+
+type GeqTables struct {
+	Users User
+	Posts Post
+}
+
+type GeqRelationships struct {
+	Posts struct {
+		Author User `geq:"Posts.WriterID = Users.ID"`
+	}
+}
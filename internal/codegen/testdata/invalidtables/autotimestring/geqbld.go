@@ -0,0 +1,12 @@
+package autotimestring
+
+import "database/sql"
+
+type Note struct {
+	ID        int64
+	CreatedAt sql.NullString `geq:"autoCreateTime"`
+}
+
+type GeqTables struct {
+	Notes Note
+}
@@ -0,0 +1,16 @@
+package autotimestruct
+
+import "time"
+
+type Timestamp struct {
+	time.Time
+}
+
+type Note struct {
+	ID        int64
+	UpdatedAt Timestamp `geq:"autoUpdateTime"`
+}
+
+type GeqTables struct {
+	Notes Note
+}
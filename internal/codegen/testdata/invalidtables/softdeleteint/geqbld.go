@@ -0,0 +1,10 @@
+package softdeleteint
+
+type Document struct {
+	ID        int64
+	DeletedAt int64 `geq:"softDelete"`
+}
+
+type GeqTables struct {
+	Documents Document
+}
@@ -0,0 +1,12 @@
+package softdeletetime
+
+import "time"
+
+type Document struct {
+	ID        int64
+	DeletedAt time.Time `geq:"softDelete"`
+}
+
+type GeqTables struct {
+	Documents Document
+}
@@ -5,9 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"go/format"
+	"go/token"
 	"go/types"
 	"os"
 	"path/filepath"
+	"reflect"
 	"slices"
 	"strings"
 	"text/template"
@@ -49,6 +51,34 @@ func lookupStruct(pkg *packages.Package, name string) (strct *types.Struct, err
 	return strct, nil
 }
 
+// lookupImportedType finds the named type from the packages imported by pkg directly or indirectly.
+// It returns nil if no such package is imported.
+func lookupImportedType(pkg *types.Package, path, name string) types.Type {
+	seen := make(map[*types.Package]struct{})
+	queue := []*types.Package{pkg}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		if _, ok := seen[p]; ok {
+			continue
+		}
+		seen[p] = struct{}{}
+		if p.Path() == path {
+			if obj, ok := p.Scope().Lookup(name).(*types.TypeName); ok {
+				return obj.Type()
+			}
+			return nil
+		}
+		queue = append(queue, p.Imports()...)
+	}
+	return nil
+}
+
+// errorAt prefixes the position of the Go source to the error.
+func errorAt(pkg *packages.Package, pos token.Pos, err error) error {
+	return fmt.Errorf("%s: %w", pkg.Fset.Position(pos), err)
+}
+
 func parseGeqConfig(pkg *packages.Package, fileNames []string, configKeys []string) (m map[string]string, err error) {
 	m = make(map[string]string, 0)
 	for i, f := range pkg.GoFiles {
@@ -82,6 +112,27 @@ func readGeqConfigValue(key, line string) (string, bool) {
 	return strings.TrimSpace(rest), true
 }
 
+// parseTagOptions parses a comma separated list of options such as "schema=billing,pk".
+func parseTagOptions(tagStr string, knownKeys []string) (opts map[string]string, err error) {
+	opts = make(map[string]string, 0)
+	value := reflect.StructTag(tagStr).Get("geq")
+	if value == "" {
+		return opts, nil
+	}
+	for _, item := range strings.Split(value, ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(item), "=")
+		k = strings.TrimSpace(k)
+		if !slices.Contains(knownKeys, k) {
+			return nil, fmt.Errorf("unknown geq tag option: %q", k)
+		}
+		if _, ok := opts[k]; ok {
+			return nil, fmt.Errorf("duplicate geq tag option: %q", k)
+		}
+		opts[k] = strings.TrimSpace(v)
+	}
+	return opts, nil
+}
+
 func buildGoCode(name string, codeTmpl string, data any) (src []byte, err error) {
 	tmpl := template.Must(template.New(name).Parse(codeTmpl))
 	buf := new(bytes.Buffer)
@@ -4,108 +4,181 @@
 package d
 
 import (
+	"database/sql"
 	"github.com/ryym/geq"
 	"github.com/ryym/geq/internal/tests/mdl"
+	"sync"
 	"time"
 )
 
 var Users = NewUsers("users")
 var Posts = NewPosts("posts")
 var Transactions = NewTransactions("transactions")
+var Invoices = NewInvoices("invoices")
+var Orders = NewOrders("orders")
+var OrderItems = NewOrderItems("order_items")
+var Groups = NewGroups("groups")
+var UserGroups = NewUserGroups("user_groups")
+var Employees = NewEmployees("employees")
+var Comments = NewComments("comments")
+var Documents = NewDocuments("documents")
+var Notes = NewNotes("notes")
+var Counters = NewCounters("counters")
 
 func init() {
 	Users.InitRelships()
 	Posts.InitRelships()
 	Transactions.InitRelships()
+	Invoices.InitRelships()
+	Orders.InitRelships()
+	OrderItems.InitRelships()
+	Groups.InitRelships()
+	UserGroups.InitRelships()
+	Employees.InitRelships()
+	Comments.InitRelships()
+	Documents.InitRelships()
+	Notes.InitRelships()
+	Counters.InitRelships()
 }
 
 type TableUsers struct {
 	*geq.TableBase
-	relshipsSet bool
-	alias       string
-	ID          *geq.Column[int64]
-	Name        *geq.Column[string]
+	relshipsOnce sync.Once
+	alias        string
+	schema       string
+	ID           *geq.Column[int64]
+	Name         *geq.Column[string]
+	Groups       *geq.ThroughRelship[*TableGroups, mdl.Group, int64]
+	Posts        *geq.Relship[*TablePosts, mdl.Post, int64]
+	Documents    *geq.Relship[*TableDocuments, mdl.Document, int64]
 }
 
 func NewUsers(alias string) *TableUsers {
+	return newUsers(alias, "")
+}
+
+func newUsers(alias, schema string) *TableUsers {
 	t := &TableUsers{
-		alias: alias,
-		ID:    geq.NewColumn[int64](alias, "id"),
-		Name:  geq.NewColumn[string](alias, "name"),
+		alias:  alias,
+		schema: schema,
+		ID:     geq.NewColumn[int64](alias, "id"),
+		Name:   geq.NewColumn[string](alias, "name"),
 	}
 	columns := []geq.AnyColumn{t.ID, t.Name}
 	sels := []geq.Selection{t.ID, t.Name}
-	t.TableBase = geq.NewTableBase("users", alias, columns, sels)
+	t.TableBase = geq.NewTableBase(schema, "users", alias, columns, sels, geq.TableOptions{
+		PKs: []geq.AnyColumn{t.ID},
+	})
 	return t
 }
 
 func (t *TableUsers) InitRelships() {
-	if t.relshipsSet {
-		return
-	}
-	t.relshipsSet = true
+	t.relshipsOnce.Do(func() {
+		func() {
+			v := newUserGroups(t.alias+"_groups_user_groups", t.schema)
+			r := newGroups(t.alias+"_groups", t.schema)
+			t.Groups = geq.NewThroughRelship(t, r, v, t.ID, v.UserID, v.GroupID, r.ID)
+		}()
+		func() {
+			r := newPosts(t.alias+"_posts", t.schema)
+			t.Posts = geq.NewRelship(t, r, t.ID, r.AuthorID)
+		}()
+		func() {
+			r := newDocuments(t.alias+"_documents", t.schema)
+			t.Documents = geq.NewRelship(t, r, t.ID, r.OwnerID)
+		}()
+	})
 }
 func (t *TableUsers) FieldPtrs(r *mdl.User) []any {
 	return []any{&r.ID, &r.Name}
 }
+func (t *TableUsers) PK() *geq.Column[int64] {
+	return t.ID
+}
 func (t *TableUsers) As(alias string) *TableUsers {
-	return NewUsers(alias)
+	return newUsers(alias, t.schema)
+}
+func (t *TableUsers) WithSchema(schema string) *TableUsers {
+	return newUsers(t.alias, schema)
 }
 
 type TablePosts struct {
 	*geq.TableBase
-	relshipsSet bool
-	alias       string
-	ID          *geq.Column[int64]
-	AuthorID    *geq.Column[int64]
-	Title       *geq.Column[string]
-	Author      *geq.Relship[*TableUsers, mdl.User, int64]
+	relshipsOnce sync.Once
+	alias        string
+	schema       string
+	ID           *geq.Column[int64]
+	AuthorID     *geq.Column[int64]
+	Title        *geq.Column[string]
+	Author       *geq.Relship[*TableUsers, mdl.User, int64]
+	Comments     *geq.Relship[*TableComments, mdl.Comment, int64]
 }
 
 func NewPosts(alias string) *TablePosts {
+	return newPosts(alias, "")
+}
+
+func newPosts(alias, schema string) *TablePosts {
 	t := &TablePosts{
 		alias:    alias,
+		schema:   schema,
 		ID:       geq.NewColumn[int64](alias, "id"),
 		AuthorID: geq.NewColumn[int64](alias, "author_id"),
 		Title:    geq.NewColumn[string](alias, "title"),
 	}
 	columns := []geq.AnyColumn{t.ID, t.AuthorID, t.Title}
 	sels := []geq.Selection{t.ID, t.AuthorID, t.Title}
-	t.TableBase = geq.NewTableBase("posts", alias, columns, sels)
+	t.TableBase = geq.NewTableBase(schema, "posts", alias, columns, sels, geq.TableOptions{
+		PKs: []geq.AnyColumn{t.ID},
+	})
 	return t
 }
 
 func (t *TablePosts) InitRelships() {
-	if t.relshipsSet {
-		return
-	}
-	func() {
-		r := NewUsers(t.alias + "_users")
-		t.Author = geq.NewRelship(r, t.AuthorID, r.ID)
-	}()
-	t.relshipsSet = true
+	t.relshipsOnce.Do(func() {
+		func() {
+			r := newUsers(t.alias+"_author", t.schema)
+			t.Author = geq.NewRelship(t, r, t.AuthorID, r.ID)
+		}()
+		func() {
+			r := newComments(t.alias+"_comments", t.schema)
+			t.Comments = geq.NewRelship(t, r, t.ID, r.PostID)
+		}()
+	})
 }
 func (t *TablePosts) FieldPtrs(r *mdl.Post) []any {
 	return []any{&r.ID, &r.AuthorID, &r.Title}
 }
+func (t *TablePosts) PK() *geq.Column[int64] {
+	return t.ID
+}
 func (t *TablePosts) As(alias string) *TablePosts {
-	return NewPosts(alias)
+	return newPosts(alias, t.schema)
+}
+func (t *TablePosts) WithSchema(schema string) *TablePosts {
+	return newPosts(t.alias, schema)
 }
 
 type TableTransactions struct {
 	*geq.TableBase
-	relshipsSet bool
-	alias       string
-	ID          *geq.Column[uint32]
-	UserID      *geq.Column[uint32]
-	Amount      *geq.Column[int32]
-	Description *geq.Column[string]
-	CreatedAt   *geq.Column[time.Time]
+	relshipsOnce sync.Once
+	alias        string
+	schema       string
+	ID           *geq.Column[uint32]
+	UserID       *geq.Column[uint32]
+	Amount       *geq.Column[int32]
+	Description  *geq.Column[string]
+	CreatedAt    *geq.Column[time.Time]
 }
 
 func NewTransactions(alias string) *TableTransactions {
+	return newTransactions(alias, "")
+}
+
+func newTransactions(alias, schema string) *TableTransactions {
 	t := &TableTransactions{
 		alias:       alias,
+		schema:      schema,
 		ID:          geq.NewColumn[uint32](alias, "id"),
 		UserID:      geq.NewColumn[uint32](alias, "user_id"),
 		Amount:      geq.NewColumn[int32](alias, "amount"),
@@ -114,21 +187,541 @@ func NewTransactions(alias string) *TableTransactions {
 	}
 	columns := []geq.AnyColumn{t.ID, t.UserID, t.Amount, t.Description, t.CreatedAt}
 	sels := []geq.Selection{t.ID, t.UserID, t.Amount, t.Description, t.CreatedAt}
-	t.TableBase = geq.NewTableBase("transactions", alias, columns, sels)
+	t.TableBase = geq.NewTableBase(schema, "transactions", alias, columns, sels, geq.TableOptions{
+		PKs: []geq.AnyColumn{t.ID},
+	})
 	return t
 }
 
 func (t *TableTransactions) InitRelships() {
-	if t.relshipsSet {
-		return
-	}
-	t.relshipsSet = true
+	t.relshipsOnce.Do(func() {
+	})
 }
 func (t *TableTransactions) FieldPtrs(r *mdl.Transaction) []any {
 	return []any{&r.ID, &r.UserID, &r.Amount, &r.Description, &r.CreatedAt}
 }
+func (t *TableTransactions) PK() *geq.Column[uint32] {
+	return t.ID
+}
 func (t *TableTransactions) As(alias string) *TableTransactions {
-	return NewTransactions(alias)
+	return newTransactions(alias, t.schema)
+}
+func (t *TableTransactions) WithSchema(schema string) *TableTransactions {
+	return newTransactions(t.alias, schema)
+}
+
+type TableInvoices struct {
+	*geq.TableBase
+	relshipsOnce sync.Once
+	alias        string
+	schema       string
+	ID           *geq.Column[int64]
+	UserID       *geq.Column[int64]
+	Amount       *geq.Column[int32]
+	User         *geq.Relship[*TableUsers, mdl.User, int64]
+}
+
+func NewInvoices(alias string) *TableInvoices {
+	return newInvoices(alias, "billing")
+}
+
+func newInvoices(alias, schema string) *TableInvoices {
+	t := &TableInvoices{
+		alias:  alias,
+		schema: schema,
+		ID:     geq.NewColumn[int64](alias, "id"),
+		UserID: geq.NewColumn[int64](alias, "user_id"),
+		Amount: geq.NewColumn[int32](alias, "amount"),
+	}
+	columns := []geq.AnyColumn{t.ID, t.UserID, t.Amount}
+	sels := []geq.Selection{t.ID, t.UserID, t.Amount}
+	t.TableBase = geq.NewTableBase(schema, "invoices", alias, columns, sels, geq.TableOptions{
+		PKs: []geq.AnyColumn{t.ID},
+	})
+	return t
+}
+
+func (t *TableInvoices) InitRelships() {
+	t.relshipsOnce.Do(func() {
+		func() {
+			r := newUsers(t.alias+"_user", "")
+			t.User = geq.NewRelship(t, r, t.UserID, r.ID)
+		}()
+	})
+}
+func (t *TableInvoices) FieldPtrs(r *mdl.Invoice) []any {
+	return []any{&r.ID, &r.UserID, &r.Amount}
+}
+func (t *TableInvoices) PK() *geq.Column[int64] {
+	return t.ID
+}
+func (t *TableInvoices) As(alias string) *TableInvoices {
+	return newInvoices(alias, t.schema)
+}
+func (t *TableInvoices) WithSchema(schema string) *TableInvoices {
+	return newInvoices(t.alias, schema)
+}
+
+type TableOrders struct {
+	*geq.TableBase
+	relshipsOnce sync.Once
+	alias        string
+	schema       string
+	TenantID     *geq.Column[int64]
+	ID           *geq.Column[int64]
+	UserID       *geq.Column[int64]
+	Items        *geq.Relship[*TableOrderItems, mdl.OrderItem, int64]
+}
+
+func NewOrders(alias string) *TableOrders {
+	return newOrders(alias, "")
+}
+
+func newOrders(alias, schema string) *TableOrders {
+	t := &TableOrders{
+		alias:    alias,
+		schema:   schema,
+		TenantID: geq.NewColumn[int64](alias, "tenant_id"),
+		ID:       geq.NewColumn[int64](alias, "id"),
+		UserID:   geq.NewColumn[int64](alias, "user_id"),
+	}
+	columns := []geq.AnyColumn{t.TenantID, t.ID, t.UserID}
+	sels := []geq.Selection{t.TenantID, t.ID, t.UserID}
+	t.TableBase = geq.NewTableBase(schema, "orders", alias, columns, sels, geq.TableOptions{
+		PKs: []geq.AnyColumn{t.TenantID, t.ID},
+	})
+	return t
+}
+
+func (t *TableOrders) InitRelships() {
+	t.relshipsOnce.Do(func() {
+		func() {
+			r := newOrderItems(t.alias+"_items", t.schema)
+			t.Items = geq.NewRelship(t, r, t.TenantID, r.TenantID,
+				geq.RelKey(t.ID, r.OrderID),
+				geq.RelFilterR(r.DeletedAt.IsNull()),
+			)
+		}()
+	})
+}
+func (t *TableOrders) FieldPtrs(r *mdl.Order) []any {
+	return []any{&r.TenantID, &r.ID, &r.UserID}
+}
+func (t *TableOrders) As(alias string) *TableOrders {
+	return newOrders(alias, t.schema)
+}
+func (t *TableOrders) WithSchema(schema string) *TableOrders {
+	return newOrders(t.alias, schema)
+}
+
+type TableOrderItems struct {
+	*geq.TableBase
+	relshipsOnce sync.Once
+	alias        string
+	schema       string
+	TenantID     *geq.Column[int64]
+	OrderID      *geq.Column[int64]
+	ID           *geq.Column[int64]
+	Name         *geq.Column[string]
+	DeletedAt    *geq.Column[sql.NullTime]
+	Order        *geq.Relship[*TableOrders, mdl.Order, int64]
+}
+
+func NewOrderItems(alias string) *TableOrderItems {
+	return newOrderItems(alias, "")
+}
+
+func newOrderItems(alias, schema string) *TableOrderItems {
+	t := &TableOrderItems{
+		alias:     alias,
+		schema:    schema,
+		TenantID:  geq.NewColumn[int64](alias, "tenant_id"),
+		OrderID:   geq.NewColumn[int64](alias, "order_id"),
+		ID:        geq.NewColumn[int64](alias, "id"),
+		Name:      geq.NewColumn[string](alias, "name"),
+		DeletedAt: geq.NewColumn[sql.NullTime](alias, "deleted_at"),
+	}
+	columns := []geq.AnyColumn{t.TenantID, t.OrderID, t.ID, t.Name, t.DeletedAt}
+	sels := []geq.Selection{t.TenantID, t.OrderID, t.ID, t.Name, t.DeletedAt}
+	t.TableBase = geq.NewTableBase(schema, "order_items", alias, columns, sels, geq.TableOptions{
+		PKs: []geq.AnyColumn{t.TenantID, t.OrderID, t.ID},
+	})
+	return t
+}
+
+func (t *TableOrderItems) InitRelships() {
+	t.relshipsOnce.Do(func() {
+		func() {
+			r := newOrders(t.alias+"_order", t.schema)
+			t.Order = geq.NewRelship(t, r, t.TenantID, r.TenantID,
+				geq.RelKey(t.OrderID, r.ID),
+			)
+		}()
+	})
+}
+func (t *TableOrderItems) FieldPtrs(r *mdl.OrderItem) []any {
+	return []any{&r.TenantID, &r.OrderID, &r.ID, &r.Name, &r.DeletedAt}
+}
+func (t *TableOrderItems) As(alias string) *TableOrderItems {
+	return newOrderItems(alias, t.schema)
+}
+func (t *TableOrderItems) WithSchema(schema string) *TableOrderItems {
+	return newOrderItems(t.alias, schema)
+}
+
+type TableGroups struct {
+	*geq.TableBase
+	relshipsOnce sync.Once
+	alias        string
+	schema       string
+	ID           *geq.Column[int64]
+	Name         *geq.Column[string]
+	Users        *geq.ThroughRelship[*TableUsers, mdl.User, int64]
+}
+
+func NewGroups(alias string) *TableGroups {
+	return newGroups(alias, "")
+}
+
+func newGroups(alias, schema string) *TableGroups {
+	t := &TableGroups{
+		alias:  alias,
+		schema: schema,
+		ID:     geq.NewColumn[int64](alias, "id"),
+		Name:   geq.NewColumn[string](alias, "name"),
+	}
+	columns := []geq.AnyColumn{t.ID, t.Name}
+	sels := []geq.Selection{t.ID, t.Name}
+	t.TableBase = geq.NewTableBase(schema, "groups", alias, columns, sels, geq.TableOptions{
+		PKs: []geq.AnyColumn{t.ID},
+	})
+	return t
+}
+
+func (t *TableGroups) InitRelships() {
+	t.relshipsOnce.Do(func() {
+		func() {
+			v := newUserGroups(t.alias+"_users_user_groups", t.schema)
+			r := newUsers(t.alias+"_users", t.schema)
+			t.Users = geq.NewThroughRelship(t, r, v, t.ID, v.GroupID, v.UserID, r.ID)
+		}()
+	})
+}
+func (t *TableGroups) FieldPtrs(r *mdl.Group) []any {
+	return []any{&r.ID, &r.Name}
+}
+func (t *TableGroups) PK() *geq.Column[int64] {
+	return t.ID
+}
+func (t *TableGroups) As(alias string) *TableGroups {
+	return newGroups(alias, t.schema)
+}
+func (t *TableGroups) WithSchema(schema string) *TableGroups {
+	return newGroups(t.alias, schema)
+}
+
+type TableUserGroups struct {
+	*geq.TableBase
+	relshipsOnce sync.Once
+	alias        string
+	schema       string
+	UserID       *geq.Column[int64]
+	GroupID      *geq.Column[int64]
+}
+
+func NewUserGroups(alias string) *TableUserGroups {
+	return newUserGroups(alias, "")
+}
+
+func newUserGroups(alias, schema string) *TableUserGroups {
+	t := &TableUserGroups{
+		alias:   alias,
+		schema:  schema,
+		UserID:  geq.NewColumn[int64](alias, "user_id"),
+		GroupID: geq.NewColumn[int64](alias, "group_id"),
+	}
+	columns := []geq.AnyColumn{t.UserID, t.GroupID}
+	sels := []geq.Selection{t.UserID, t.GroupID}
+	t.TableBase = geq.NewTableBase(schema, "user_groups", alias, columns, sels, geq.TableOptions{
+		PKs: []geq.AnyColumn{},
+	})
+	return t
+}
+
+func (t *TableUserGroups) InitRelships() {
+	t.relshipsOnce.Do(func() {
+	})
+}
+func (t *TableUserGroups) FieldPtrs(r *mdl.UserGroup) []any {
+	return []any{&r.UserID, &r.GroupID}
+}
+func (t *TableUserGroups) As(alias string) *TableUserGroups {
+	return newUserGroups(alias, t.schema)
+}
+func (t *TableUserGroups) WithSchema(schema string) *TableUserGroups {
+	return newUserGroups(t.alias, schema)
+}
+
+type TableEmployees struct {
+	*geq.TableBase
+	relshipsOnce sync.Once
+	alias        string
+	schema       string
+	ID           *geq.Column[int64]
+	Name         *geq.Column[string]
+	ManagerID    *geq.Column[int64]
+	Manager      *geq.Relship[*TableEmployees, mdl.Employee, int64]
+	Reports      *geq.Relship[*TableEmployees, mdl.Employee, int64]
+}
+
+func NewEmployees(alias string) *TableEmployees {
+	return newEmployees(alias, "")
+}
+
+func newEmployees(alias, schema string) *TableEmployees {
+	t := &TableEmployees{
+		alias:     alias,
+		schema:    schema,
+		ID:        geq.NewColumn[int64](alias, "id"),
+		Name:      geq.NewColumn[string](alias, "name"),
+		ManagerID: geq.NewColumn[int64](alias, "manager_id"),
+	}
+	columns := []geq.AnyColumn{t.ID, t.Name, t.ManagerID}
+	sels := []geq.Selection{t.ID, t.Name, t.ManagerID}
+	t.TableBase = geq.NewTableBase(schema, "employees", alias, columns, sels, geq.TableOptions{
+		PKs: []geq.AnyColumn{t.ID},
+	})
+	return t
+}
+
+func (t *TableEmployees) InitRelships() {
+	t.relshipsOnce.Do(func() {
+		func() {
+			r := newEmployees(t.alias+"_manager", t.schema)
+			t.Manager = geq.NewRelship(t, r, t.ManagerID, r.ID)
+		}()
+		func() {
+			r := newEmployees(t.alias+"_reports", t.schema)
+			t.Reports = geq.NewRelship(t, r, t.ID, r.ManagerID)
+		}()
+	})
+}
+func (t *TableEmployees) FieldPtrs(r *mdl.Employee) []any {
+	return []any{&r.ID, &r.Name, &r.ManagerID}
+}
+func (t *TableEmployees) PK() *geq.Column[int64] {
+	return t.ID
+}
+func (t *TableEmployees) As(alias string) *TableEmployees {
+	return newEmployees(alias, t.schema)
+}
+func (t *TableEmployees) WithSchema(schema string) *TableEmployees {
+	return newEmployees(t.alias, schema)
+}
+
+type TableComments struct {
+	*geq.TableBase
+	relshipsOnce sync.Once
+	alias        string
+	schema       string
+	ID           *geq.Column[int64]
+	PostID       *geq.Column[int64]
+	Body         *geq.Column[string]
+}
+
+func NewComments(alias string) *TableComments {
+	return newComments(alias, "")
+}
+
+func newComments(alias, schema string) *TableComments {
+	t := &TableComments{
+		alias:  alias,
+		schema: schema,
+		ID:     geq.NewColumn[int64](alias, "id"),
+		PostID: geq.NewColumn[int64](alias, "post_id"),
+		Body:   geq.NewColumn[string](alias, "body"),
+	}
+	columns := []geq.AnyColumn{t.ID, t.PostID, t.Body}
+	sels := []geq.Selection{t.ID, t.PostID, t.Body}
+	t.TableBase = geq.NewTableBase(schema, "comments", alias, columns, sels, geq.TableOptions{
+		PKs: []geq.AnyColumn{t.ID},
+	})
+	return t
+}
+
+func (t *TableComments) InitRelships() {
+	t.relshipsOnce.Do(func() {
+	})
+}
+func (t *TableComments) FieldPtrs(r *mdl.Comment) []any {
+	return []any{&r.ID, &r.PostID, &r.Body}
+}
+func (t *TableComments) PK() *geq.Column[int64] {
+	return t.ID
+}
+func (t *TableComments) As(alias string) *TableComments {
+	return newComments(alias, t.schema)
+}
+func (t *TableComments) WithSchema(schema string) *TableComments {
+	return newComments(t.alias, schema)
+}
+
+type TableDocuments struct {
+	*geq.TableBase
+	relshipsOnce sync.Once
+	alias        string
+	schema       string
+	ID           *geq.Column[int64]
+	OwnerID      *geq.Column[int64]
+	Title        *geq.Column[string]
+	Version      *geq.Column[int64]
+	DeletedAt    *geq.Column[sql.NullTime]
+	Owner        *geq.Relship[*TableUsers, mdl.User, int64]
+}
+
+func NewDocuments(alias string) *TableDocuments {
+	return newDocuments(alias, "")
+}
+
+func newDocuments(alias, schema string) *TableDocuments {
+	t := &TableDocuments{
+		alias:     alias,
+		schema:    schema,
+		ID:        geq.NewColumn[int64](alias, "id"),
+		OwnerID:   geq.NewColumn[int64](alias, "owner_id"),
+		Title:     geq.NewColumn[string](alias, "title"),
+		Version:   geq.NewColumn[int64](alias, "version"),
+		DeletedAt: geq.NewColumn[sql.NullTime](alias, "deleted_at"),
+	}
+	columns := []geq.AnyColumn{t.ID, t.OwnerID, t.Title, t.Version, t.DeletedAt}
+	sels := []geq.Selection{t.ID, t.OwnerID, t.Title, t.Version, t.DeletedAt}
+	t.TableBase = geq.NewTableBase(schema, "documents", alias, columns, sels, geq.TableOptions{
+		PKs:        []geq.AnyColumn{t.ID},
+		Version:    t.Version,
+		SoftDelete: t.DeletedAt,
+	})
+	return t
+}
+
+func (t *TableDocuments) InitRelships() {
+	t.relshipsOnce.Do(func() {
+		func() {
+			r := newUsers(t.alias+"_owner", t.schema)
+			t.Owner = geq.NewRelship(t, r, t.OwnerID, r.ID)
+		}()
+	})
+}
+func (t *TableDocuments) FieldPtrs(r *mdl.Document) []any {
+	return []any{&r.ID, &r.OwnerID, &r.Title, &r.Version, &r.DeletedAt}
+}
+func (t *TableDocuments) PK() *geq.Column[int64] {
+	return t.ID
+}
+func (t *TableDocuments) As(alias string) *TableDocuments {
+	return newDocuments(alias, t.schema)
+}
+func (t *TableDocuments) WithSchema(schema string) *TableDocuments {
+	return newDocuments(t.alias, schema)
+}
+
+type TableNotes struct {
+	*geq.TableBase
+	relshipsOnce sync.Once
+	alias        string
+	schema       string
+	ID           *geq.Column[int64]
+	Body         *geq.Column[string]
+	CreatedAt    *geq.Column[time.Time]
+	UpdatedAt    *geq.Column[time.Time]
+}
+
+func NewNotes(alias string) *TableNotes {
+	return newNotes(alias, "")
+}
+
+func newNotes(alias, schema string) *TableNotes {
+	t := &TableNotes{
+		alias:     alias,
+		schema:    schema,
+		ID:        geq.NewColumn[int64](alias, "id"),
+		Body:      geq.NewColumn[string](alias, "body"),
+		CreatedAt: geq.NewColumn[time.Time](alias, "created_at"),
+		UpdatedAt: geq.NewColumn[time.Time](alias, "updated_at"),
+	}
+	columns := []geq.AnyColumn{t.ID, t.Body, t.CreatedAt, t.UpdatedAt}
+	sels := []geq.Selection{t.ID, t.Body, t.CreatedAt, t.UpdatedAt}
+	t.TableBase = geq.NewTableBase(schema, "notes", alias, columns, sels, geq.TableOptions{
+		PKs:            []geq.AnyColumn{t.ID},
+		AutoCreateTime: t.CreatedAt,
+		AutoUpdateTime: t.UpdatedAt,
+	})
+	return t
+}
+
+func (t *TableNotes) InitRelships() {
+	t.relshipsOnce.Do(func() {
+	})
+}
+func (t *TableNotes) FieldPtrs(r *mdl.Note) []any {
+	return []any{&r.ID, &r.Body, &r.CreatedAt, &r.UpdatedAt}
+}
+func (t *TableNotes) PK() *geq.Column[int64] {
+	return t.ID
+}
+func (t *TableNotes) As(alias string) *TableNotes {
+	return newNotes(alias, t.schema)
+}
+func (t *TableNotes) WithSchema(schema string) *TableNotes {
+	return newNotes(t.alias, schema)
+}
+
+type TableCounters struct {
+	*geq.TableBase
+	relshipsOnce sync.Once
+	alias        string
+	schema       string
+	ID           *geq.Column[int64]
+	Version      *geq.Column[int64]
+	UpdatedAt    *geq.Column[time.Time]
+}
+
+func NewCounters(alias string) *TableCounters {
+	return newCounters(alias, "")
+}
+
+func newCounters(alias, schema string) *TableCounters {
+	t := &TableCounters{
+		alias:     alias,
+		schema:    schema,
+		ID:        geq.NewColumn[int64](alias, "id"),
+		Version:   geq.NewColumn[int64](alias, "version"),
+		UpdatedAt: geq.NewColumn[time.Time](alias, "updated_at"),
+	}
+	columns := []geq.AnyColumn{t.ID, t.Version, t.UpdatedAt}
+	sels := []geq.Selection{t.ID, t.Version, t.UpdatedAt}
+	t.TableBase = geq.NewTableBase(schema, "counters", alias, columns, sels, geq.TableOptions{
+		PKs:            []geq.AnyColumn{t.ID},
+		Version:        t.Version,
+		AutoUpdateTime: t.UpdatedAt,
+	})
+	return t
+}
+
+func (t *TableCounters) InitRelships() {
+	t.relshipsOnce.Do(func() {
+	})
+}
+func (t *TableCounters) FieldPtrs(r *mdl.Counter) []any {
+	return []any{&r.ID, &r.Version, &r.UpdatedAt}
+}
+func (t *TableCounters) PK() *geq.Column[int64] {
+	return t.ID
+}
+func (t *TableCounters) As(alias string) *TableCounters {
+	return newCounters(alias, t.schema)
+}
+func (t *TableCounters) WithSchema(schema string) *TableCounters {
+	return newCounters(t.alias, schema)
 }
 
 type PostStats struct {
@@ -1,11 +1,20 @@
 package tests
 
 import (
+	"encoding/base64"
 	"testing"
 
 	"github.com/ryym/geq"
+	"github.com/ryym/geq/internal/tests/d"
 )
 
+// customDialect implements only the methods required by geq.Dialect.
+type customDialect struct{}
+
+func (d *customDialect) Placeholder(typeName string, prevArgs []any) string { return "?" }
+func (d *customDialect) Ident(v string) string                              { return v }
+func (d *customDialect) StrConcatType() geq.StrConcatType                   { return geq.StrConcatStandard }
+
 func TestQueryVariations(t *testing.T) {
 	q := geq.Select(geq.Concat("a", "b", "c"))
 	err := assertQueryWith(&geq.DialectGeneric{}, q, "SELECT ? || ? || ?", "a", "b", "c")
@@ -21,3 +30,250 @@ func TestQueryVariations(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestIdentQuoting(t *testing.T) {
+	q := geq.SelectFrom(d.Posts.As("order"), d.Posts.As("order").ID.As("user")).
+		InnerJoin(d.Users.As("group"), d.Users.As("group").ID.Eq(d.Posts.As("order").AuthorID))
+	err := assertQueryWith(&geq.DialectPostgres{}, q, sjoin(
+		`SELECT "order"."id" AS "user" FROM "posts" AS "order"`,
+		`INNER JOIN "users" AS "group" ON "group"."id" = "order"."author_id"`,
+	))
+	if err != nil {
+		t.Error(err)
+	}
+	err = assertQueryWith(&geq.DialectMySQL{MinimalQuoting: true}, q, sjoin(
+		"SELECT `order`.id AS `user` FROM posts AS `order`",
+		"INNER JOIN users AS `group` ON `group`.id = `order`.author_id",
+	))
+	if err != nil {
+		t.Error(err)
+	}
+
+	ins := geq.InsertInto(d.Users).Values(d.Users.ID.Set(1), d.Users.Name.Set("a"))
+	err = assertQueryWith(&geq.DialectMySQL{}, ins, "INSERT INTO `users` (`id`, `name`) VALUES (?, ?)", int64(1), "a")
+	if err != nil {
+		t.Error(err)
+	}
+
+	for _, c := range []struct {
+		dialect geq.Dialect
+		ident   string
+		want    string
+	}{
+		{&geq.DialectPostgres{}, `a"b`, `"a""b"`},
+		{&geq.DialectMySQL{}, "a`b", "`a``b`"},
+		{&geq.DialectPostgres{MinimalQuoting: true}, "title", "title"},
+		{&geq.DialectPostgres{MinimalQuoting: true}, "Title", `"Title"`},
+		{&geq.DialectPostgres{MinimalQuoting: true}, "order", `"order"`},
+		{&geq.DialectMySQL{MinimalQuoting: true}, "2nd", "`2nd`"},
+	} {
+		err = assertEqual(c.dialect.Ident(c.ident), c.want)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestKeysetComparison(t *testing.T) {
+	cursor := base64.RawURLEncoding.EncodeToString([]byte(`{"v":[3,5]}`))
+	q := geq.SelectFrom(d.Posts).Where(d.Posts.Title.Neq(""))
+
+	pq, err := geq.Paginate(q, 10, cursor, d.Posts.AuthorID, d.Posts.ID).Query()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = assertQueryWith(&geq.DialectMySQL{MinimalQuoting: true}, pq, sjoin(
+		"SELECT posts.id, posts.author_id, posts.title FROM posts",
+		"WHERE posts.title <> ? AND (posts.author_id > ? OR posts.author_id = ? AND posts.id > ?)",
+		"ORDER BY posts.author_id, posts.id LIMIT 11",
+	), "", int64(3), int64(3), int64(5))
+	if err != nil {
+		t.Error(err)
+	}
+
+	// Dialects without RowCompareType compare row values directly.
+	err = assertQueryWith(&customDialect{}, pq, sjoin(
+		"SELECT posts.id, posts.author_id, posts.title FROM posts",
+		"WHERE posts.title <> ? AND (posts.author_id, posts.id) > (?, ?)",
+		"ORDER BY posts.author_id, posts.id LIMIT 11",
+	), "", int64(3), int64(5))
+	if err != nil {
+		t.Error(err)
+	}
+
+	pq, err = geq.Paginate(q, 10, cursor, d.Posts.AuthorID.Asc(), d.Posts.ID.Desc()).Query()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = assertQueryWith(&geq.DialectPostgres{MinimalQuoting: true}, pq, sjoin(
+		"SELECT posts.id, posts.author_id, posts.title FROM posts",
+		"WHERE posts.title <> $1 AND (posts.author_id > $2 OR posts.author_id = $3 AND posts.id < $4)",
+		"ORDER BY posts.author_id, posts.id DESC LIMIT 11",
+	), "", int64(3), int64(3), int64(5))
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMultiTableUpdate(t *testing.T) {
+	q := geq.Update(d.Posts).
+		Set(d.Posts.Title.Set("hello")).
+		From(d.Users).
+		Where(d.Posts.AuthorID.Eq(d.Users.ID), d.Users.Name.Eq("foo"))
+	err := assertQueryWith(&geq.DialectPostgres{MinimalQuoting: true}, q, sjoin(
+		"UPDATE posts SET title = $1 FROM users",
+		"WHERE posts.author_id = users.id AND users.name = $2",
+	), "hello", "foo")
+	if err != nil {
+		t.Error(err)
+	}
+	err = assertQueryWith(&geq.DialectMySQL{MinimalQuoting: true}, q, sjoin(
+		"UPDATE posts, users SET posts.title = ?",
+		"WHERE posts.author_id = users.id AND users.name = ?",
+	), "hello", "foo")
+	if err != nil {
+		t.Error(err)
+	}
+	// Dialects without ModifyStyle use the standard style.
+	err = assertQueryWith(&customDialect{}, q, sjoin(
+		"UPDATE posts SET title = ? FROM users",
+		"WHERE posts.author_id = users.id AND users.name = ?",
+	), "hello", "foo")
+	if err != nil {
+		t.Error(err)
+	}
+
+	jq := geq.Update(d.Posts).
+		Set(d.Posts.Title.Set("hello")).
+		JoinRels(d.Posts.Author).
+		Where(d.Posts.Author.T().Name.Eq("foo"))
+	err = assertQueryWith(&geq.DialectMySQL{MinimalQuoting: true}, jq, sjoin(
+		"UPDATE posts INNER JOIN users AS posts_author ON posts.author_id = posts_author.id",
+		"SET posts.title = ? WHERE posts_author.name = ?",
+	), "hello", "foo")
+	if err != nil {
+		t.Error(err)
+	}
+	_, err = jq.BuildWith(geq.NewQueryConfig(&geq.DialectPostgres{}))
+	if err == nil {
+		t.Error("UPDATE JOIN must not be built for PostgreSQL")
+	}
+
+	p := d.Posts.As("p")
+	aq := geq.Update(p).
+		Set(p.Title.Set("hello")).
+		InnerJoin(d.Posts, d.Posts.ID.Eq(p.ID)).
+		Where(d.Posts.AuthorID.Eq(int64(1)))
+	err = assertQueryWith(&geq.DialectMySQL{MinimalQuoting: true}, aq, sjoin(
+		"UPDATE posts AS p INNER JOIN posts ON posts.id = p.id",
+		"SET p.title = ? WHERE posts.author_id = ?",
+	), "hello", int64(1))
+	if err != nil {
+		t.Error(err)
+	}
+	fq := geq.Update(p).Set(p.Title.Set("hello")).From(d.Users).Where(d.Users.ID.Eq(p.AuthorID))
+	err = assertQueryWith(&geq.DialectPostgres{MinimalQuoting: true}, fq,
+		"UPDATE posts AS p SET title = $1 FROM users WHERE users.id = p.author_id",
+		"hello",
+	)
+	if err != nil {
+		t.Error(err)
+	}
+
+	lq := geq.Update(d.Posts).
+		Set(d.Posts.Title.Set("hello")).
+		Where(d.Posts.AuthorID.Eq(int64(1))).
+		OrderBy(d.Posts.ID.Desc()).
+		Limit(100)
+	err = assertQueryWith(&geq.DialectMySQL{MinimalQuoting: true}, lq, sjoin(
+		"UPDATE posts SET title = ? WHERE posts.author_id = ?",
+		"ORDER BY posts.id DESC LIMIT 100",
+	), "hello", int64(1))
+	if err != nil {
+		t.Error(err)
+	}
+	_, err = lq.BuildWith(geq.NewQueryConfig(&geq.DialectPostgres{}))
+	if err == nil {
+		t.Error("UPDATE with LIMIT must not be built for PostgreSQL")
+	}
+	_, err = lq.From(d.Users).BuildWith(geq.NewQueryConfig(&geq.DialectMySQL{}))
+	if err == nil {
+		t.Error("multiple table UPDATE with LIMIT must not be built for MySQL")
+	}
+}
+
+func TestMultiTableDelete(t *testing.T) {
+	q := geq.DeleteFrom(d.Posts).
+		Using(d.Users).
+		Where(d.Posts.AuthorID.Eq(d.Users.ID), d.Users.Name.Eq("foo"))
+	err := assertQueryWith(&geq.DialectPostgres{MinimalQuoting: true}, q, sjoin(
+		"DELETE FROM posts USING users",
+		"WHERE posts.author_id = users.id AND users.name = $1",
+	), "foo")
+	if err != nil {
+		t.Error(err)
+	}
+	err = assertQueryWith(&geq.DialectMySQL{MinimalQuoting: true}, q, sjoin(
+		"DELETE posts FROM posts, users",
+		"WHERE posts.author_id = users.id AND users.name = ?",
+	), "foo")
+	if err != nil {
+		t.Error(err)
+	}
+
+	jq := geq.DeleteFrom(d.Posts).
+		JoinRels(d.Posts.Author).
+		Where(d.Posts.Author.T().Name.Eq("foo"))
+	err = assertQueryWith(&geq.DialectMySQL{MinimalQuoting: true}, jq, sjoin(
+		"DELETE posts FROM posts INNER JOIN users AS posts_author ON posts.author_id = posts_author.id",
+		"WHERE posts_author.name = ?",
+	), "foo")
+	if err != nil {
+		t.Error(err)
+	}
+	_, err = jq.BuildWith(geq.NewQueryConfig(&geq.DialectPostgres{}))
+	if err == nil {
+		t.Error("DELETE with JOIN must not be built for PostgreSQL")
+	}
+
+	p := d.Posts.As("p")
+	aq := geq.DeleteFrom(p).Using(d.Users).Where(p.AuthorID.Eq(d.Users.ID))
+	err = assertQueryWith(&geq.DialectMySQL{MinimalQuoting: true}, aq,
+		"DELETE p FROM posts AS p, users WHERE p.author_id = users.id",
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	err = assertQueryWith(&geq.DialectPostgres{MinimalQuoting: true}, aq,
+		"DELETE FROM posts AS p USING users WHERE p.author_id = users.id",
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	err = assertQueryWith(&geq.DialectMySQL{MinimalQuoting: true}, geq.DeleteFrom(p).Where(p.ID.Eq(int64(1))),
+		"DELETE FROM posts AS p WHERE p.id = ?", int64(1),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+
+	lq := geq.DeleteFrom(d.Posts).
+		Where(d.Posts.AuthorID.Eq(int64(1))).
+		OrderBy(d.Posts.ID).
+		Limit(100)
+	err = assertQueryWith(&geq.DialectMySQL{MinimalQuoting: true}, lq, sjoin(
+		"DELETE FROM posts WHERE posts.author_id = ?",
+		"ORDER BY posts.id LIMIT 100",
+	), int64(1))
+	if err != nil {
+		t.Error(err)
+	}
+	_, err = lq.BuildWith(geq.NewQueryConfig(&geq.DialectPostgres{}))
+	if err == nil {
+		t.Error("DELETE with LIMIT must not be built for PostgreSQL")
+	}
+	_, err = lq.Using(d.Users).BuildWith(geq.NewQueryConfig(&geq.DialectMySQL{}))
+	if err == nil {
+		t.Error("multiple table DELETE with LIMIT must not be built for MySQL")
+	}
+}
@@ -3,7 +3,9 @@ package tests
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -392,7 +394,7 @@ func runIntegrationTest(t *testing.T, db *sql.DB) {
 				q := geq.SelectFrom(d.Posts).JoinRels(d.Posts.Author).OrderBy(d.Posts.AuthorID)
 				err = assertQuery(q, sjoin(
 					"SELECT posts.id, posts.author_id, posts.title FROM posts",
-					"INNER JOIN users AS posts_users ON posts.author_id = posts_users.id",
+					"INNER JOIN users AS posts_author ON posts.author_id = posts_author.id",
 					"ORDER BY posts.author_id",
 				))
 				if err != nil {
@@ -954,5 +956,1029 @@ func runIntegrationTest(t *testing.T, db *sql.DB) {
 				return nil
 			},
 		},
+		{
+			name: "build queries for schema-qualified tables",
+			run: func(db *sql.Tx) (err error) {
+				q := geq.SelectFrom(d.Invoices).JoinRels(d.Invoices.User).Where(d.Invoices.Amount.Gt(0))
+				err = assertQuery(q, sjoin(
+					"SELECT invoices.id, invoices.user_id, invoices.amount FROM billing.invoices",
+					"INNER JOIN users AS invoices_user ON invoices.user_id = invoices_user.id",
+					"WHERE invoices.amount > ?",
+				), 0)
+				if err != nil {
+					return err
+				}
+
+				ins := geq.InsertInto(d.Invoices).Values(d.Invoices.UserID.Set(1), d.Invoices.Amount.Set(5))
+				err = assertQuery(ins, "INSERT INTO billing.invoices (user_id, amount) VALUES (?, ?)", int64(1), int32(5))
+				if err != nil {
+					return err
+				}
+
+				upd := geq.Update(d.Invoices).Set(d.Invoices.Amount.Set(5)).Where(d.Invoices.ID.Eq(1))
+				err = assertQuery(upd, "UPDATE billing.invoices SET amount = ? WHERE invoices.id = ?", int32(5), 1)
+				if err != nil {
+					return err
+				}
+
+				del := geq.DeleteFrom(d.Invoices).Where(d.Invoices.ID.Eq(1))
+				err = assertQuery(del, "DELETE FROM billing.invoices WHERE invoices.id = ?", 1)
+				if err != nil {
+					return err
+				}
+				return nil
+			},
+		},
+		{
+			name: "switch table schema at runtime",
+			run: func(db *sql.Tx) (err error) {
+				posts := d.Posts.WithSchema("tenant1")
+				posts.InitRelships()
+				q := geq.SelectFrom(posts).JoinRels(posts.Author)
+				err = assertQuery(q, sjoin(
+					"SELECT posts.id, posts.author_id, posts.title FROM tenant1.posts",
+					"INNER JOIN tenant1.users AS posts_author ON posts.author_id = posts_author.id",
+				))
+				if err != nil {
+					return err
+				}
+
+				invoices := d.Invoices.WithSchema("tenant1").As("i")
+				invoices.InitRelships()
+				q2 := geq.SelectOnly(invoices.ID).From(invoices).JoinRels(invoices.User)
+				err = assertQuery(q2, sjoin(
+					"SELECT i.id FROM tenant1.invoices AS i",
+					"INNER JOIN users AS i_user ON i.user_id = i_user.id",
+				))
+				if err != nil {
+					return err
+				}
+				return nil
+			},
+		},
+		{
+			name: "left join using relationship",
+			data: `
+				INSERT INTO posts (id, author_id, title) VALUES (7, 99, 'orphan-post');
+			`,
+			run: func(db *sql.Tx) (err error) {
+				q := geq.SelectFrom(d.Posts).LeftJoinRels(d.Posts.Author).Where(d.Posts.ID.Gte(6)).OrderBy(d.Posts.ID)
+				err = assertQuery(q, sjoin(
+					"SELECT posts.id, posts.author_id, posts.title FROM posts",
+					"LEFT JOIN users AS posts_author ON posts.author_id = posts_author.id",
+					"WHERE posts.id >= ? ORDER BY posts.id",
+				), 6)
+				if err != nil {
+					return err
+				}
+
+				var posts []mdl.Post
+				var authors []*mdl.User
+				var authorMap map[int64]mdl.User
+				err = q.WillScan(
+					geq.ToSlice(d.Posts, &posts),
+					geq.ToSliceOptional(d.Posts.Author, d.Posts.Author.T().ID, &authors),
+					geq.ToMapOptional(d.Posts.Author, d.Posts.Author.T().ID, &authorMap),
+				).Load(ctx, db)
+				if err != nil {
+					return err
+				}
+				err = assertEqual(posts, []mdl.Post{
+					{ID: 6, AuthorID: 3, Title: "user3-post3"},
+					{ID: 7, AuthorID: 99, Title: "orphan-post"},
+				})
+				if err != nil {
+					return err
+				}
+				err = assertEqual(authors, []*mdl.User{{ID: 3, Name: "user3"}, nil})
+				if err != nil {
+					return err
+				}
+				err = assertEqual(authorMap, map[int64]mdl.User{3: {ID: 3, Name: "user3"}})
+				if err != nil {
+					return err
+				}
+				return nil
+			},
+		},
+		{
+			name: "join using composite key relationship",
+			data: `
+				INSERT INTO orders (tenant_id, id, user_id) VALUES (1, 1, 1), (2, 1, 2);
+				INSERT INTO order_items (tenant_id, order_id, id, name, deleted_at) VALUES
+					(1, 1, 1, 'item1', NULL),
+					(1, 1, 2, 'item2', '2023-09-24 08:45:01'),
+					(2, 1, 1, 'item3', NULL);
+			`,
+			run: func(db *sql.Tx) (err error) {
+				q := geq.SelectOnly(d.Orders.Items.T().Name).From(d.Orders).JoinRels(d.Orders.Items).
+					Where(d.Orders.TenantID.Eq(1)).
+					OrderBy(d.Orders.Items.T().ID)
+				err = assertQuery(q, sjoin(
+					"SELECT orders_items.name FROM orders",
+					"INNER JOIN order_items AS orders_items",
+					"ON orders.tenant_id = orders_items.tenant_id",
+					"AND orders.id = orders_items.order_id",
+					"AND orders_items.deleted_at IS NULL",
+					"WHERE orders.tenant_id = ? ORDER BY orders_items.id",
+				), 1)
+				if err != nil {
+					return err
+				}
+				names, err := q.Load(ctx, db)
+				if err != nil {
+					return err
+				}
+				err = assertEqual(names, []string{"item1"})
+				if err != nil {
+					return err
+				}
+				return nil
+			},
+		},
+		{
+			name: "select via composite key relationship",
+			run: func(db *sql.Tx) (err error) {
+				items := []mdl.OrderItem{
+					{TenantID: 1, OrderID: 1, ID: 1},
+					{TenantID: 2, OrderID: 3, ID: 1},
+				}
+				q := geq.SelectVia(items, d.Orders, d.Orders.Items)
+				err = assertQuery(q, sjoin(
+					"SELECT orders.tenant_id, orders.id, orders.user_id FROM orders",
+					"WHERE (orders.tenant_id, orders.id) IN ((?, ?), (?, ?))",
+				), int64(1), int64(1), int64(2), int64(3))
+				if err != nil {
+					return err
+				}
+				return nil
+			},
+		},
+		{
+			name: "join using many-to-many relationship",
+			data: `
+				INSERT INTO user_groups (user_id, group_id) VALUES (1, 1), (1, 2), (2, 2);
+			`,
+			run: func(db *sql.Tx) (err error) {
+				err = insertGroups(ctx, db)
+				if err != nil {
+					return err
+				}
+				q := geq.SelectFrom(d.Users).Distinct().JoinRels(d.Users.Groups).
+					Where(d.Users.Groups.T().Name.Eq("group2")).
+					OrderBy(d.Users.ID)
+				err = assertQuery(q, sjoin(
+					"SELECT DISTINCT users.id, users.name FROM users",
+					"INNER JOIN user_groups AS users_groups_user_groups ON users.id = users_groups_user_groups.user_id",
+					"INNER JOIN groups AS users_groups ON users_groups_user_groups.group_id = users_groups.id",
+					"WHERE users_groups.name = ? ORDER BY users.id",
+				), "group2")
+				if err != nil {
+					return err
+				}
+				users, err := q.Load(ctx, db)
+				if err != nil {
+					return err
+				}
+				err = assertEqual(users, []mdl.User{{ID: 1, Name: "user1"}, {ID: 2, Name: "user2"}})
+				if err != nil {
+					return err
+				}
+				return nil
+			},
+		},
+		{
+			name: "select via many-to-many relationship",
+			data: `
+				INSERT INTO user_groups (user_id, group_id) VALUES (1, 1), (1, 2), (2, 2);
+			`,
+			run: func(db *sql.Tx) (err error) {
+				err = insertGroups(ctx, db)
+				if err != nil {
+					return err
+				}
+				groups := []mdl.Group{{ID: 1, Name: "group1"}}
+				q := geq.SelectVia(groups, d.Users, d.Users.Groups).OrderBy(d.Users.ID)
+				err = assertQuery(q, sjoin(
+					"SELECT users.id, users.name FROM users",
+					"WHERE users.id IN (SELECT users_groups_user_groups.user_id FROM user_groups AS users_groups_user_groups",
+					"WHERE users_groups_user_groups.group_id IN (?))",
+					"ORDER BY users.id",
+				), int64(1))
+				if err != nil {
+					return err
+				}
+				users, err := q.Load(ctx, db)
+				if err != nil {
+					return err
+				}
+				err = assertEqual(users, []mdl.User{{ID: 1, Name: "user1"}})
+				if err != nil {
+					return err
+				}
+
+				q = geq.SelectVia([]mdl.Group{}, d.Users, d.Users.Groups)
+				err = assertQuery(q, "SELECT users.id, users.name FROM users WHERE 1 = 0")
+				if err != nil {
+					return err
+				}
+				users, err = q.Load(ctx, db)
+				if err != nil {
+					return err
+				}
+				err = assertEqual(len(users), 0)
+				if err != nil {
+					return err
+				}
+
+				groupsMap, err := d.Users.Groups.LoadMap(ctx, db, []int64{1, 2, 3})
+				if err != nil {
+					return err
+				}
+				err = assertEqual(groupsMap, map[int64][]mdl.Group{
+					1: {{ID: 1, Name: "group1"}, {ID: 2, Name: "group2"}},
+					2: {{ID: 2, Name: "group2"}},
+				})
+				if err != nil {
+					return err
+				}
+
+				groupsMap, err = d.Users.Groups.LoadMap(ctx, db, nil)
+				if err != nil {
+					return err
+				}
+				err = assertEqual(groupsMap, map[int64][]mdl.Group{})
+				if err != nil {
+					return err
+				}
+				return nil
+			},
+		},
+		{
+			name: "load related records by relationships",
+			data: `
+				INSERT INTO user_groups (user_id, group_id) VALUES (1, 1), (1, 2), (2, 2);
+			`,
+			run: func(db *sql.Tx) (err error) {
+				err = insertGroups(ctx, db)
+				if err != nil {
+					return err
+				}
+				users := []mdl.User{{ID: 1, Name: "user1"}, {ID: 2, Name: "user2"}, {ID: 1, Name: "user1"}}
+				postsMap, err := geq.LoadRelated(ctx, db, users, d.Users.Posts)
+				if err != nil {
+					return err
+				}
+				err = assertEqual(postsMap, map[int64][]mdl.Post{
+					1: {{ID: 1, AuthorID: 1, Title: "user1-post1"}, {ID: 2, AuthorID: 1, Title: "user1-post2"}},
+					2: {{ID: 3, AuthorID: 2, Title: "user2-post1"}},
+				})
+				if err != nil {
+					return err
+				}
+
+				posts := []mdl.Post{{ID: 3, AuthorID: 2}, {ID: 4, AuthorID: 3}}
+				authorsMap, err := geq.LoadRelated(ctx, db, posts, d.Posts.Author)
+				if err != nil {
+					return err
+				}
+				err = assertEqual(authorsMap, map[int64][]mdl.User{
+					2: {{ID: 2, Name: "user2"}},
+					3: {{ID: 3, Name: "user3"}},
+				})
+				if err != nil {
+					return err
+				}
+
+				groupsMap, err := geq.LoadRelated(ctx, db, users, d.Users.Groups)
+				if err != nil {
+					return err
+				}
+				err = assertEqual(groupsMap, map[int64][]mdl.Group{
+					1: {{ID: 1, Name: "group1"}, {ID: 2, Name: "group2"}},
+					2: {{ID: 2, Name: "group2"}},
+				})
+				if err != nil {
+					return err
+				}
+
+				emptyMap, err := geq.LoadRelated(ctx, db, []mdl.User{}, d.Users.Posts)
+				if err != nil {
+					return err
+				}
+				err = assertEqual(emptyMap, map[int64][]mdl.Post{})
+				if err != nil {
+					return err
+				}
+				return nil
+			},
+		},
+		{
+			name: "join self-referential relationships",
+			data: `
+				INSERT INTO employees (id, name, manager_id) VALUES (1, 'boss', 0), (2, 'emp2', 1), (3, 'emp3', 1), (4, 'emp4', 2);
+			`,
+			run: func(db *sql.Tx) (err error) {
+				var emps []mdl.Employee
+				var managers map[int64]mdl.Employee
+				q := geq.SelectFrom(d.Employees).JoinRels(d.Employees.Manager).
+					Where(d.Employees.Manager.T().Name.Eq("boss")).
+					OrderBy(d.Employees.ID)
+				err = assertQuery(q, sjoin(
+					"SELECT employees.id, employees.name, employees.manager_id FROM employees",
+					"INNER JOIN employees AS employees_manager ON employees.manager_id = employees_manager.id",
+					"WHERE employees_manager.name = ? ORDER BY employees.id",
+				), "boss")
+				if err != nil {
+					return err
+				}
+				err = q.WillScan(
+					geq.ToSlice(d.Employees, &emps),
+					geq.ToMap(d.Employees.Manager, d.Employees.Manager.T().ID, &managers),
+				).Load(ctx, db)
+				if err != nil {
+					return err
+				}
+				err = assertEqual(emps, []mdl.Employee{
+					{ID: 2, Name: "emp2", ManagerID: 1},
+					{ID: 3, Name: "emp3", ManagerID: 1},
+				})
+				if err != nil {
+					return err
+				}
+				err = assertEqual(managers, map[int64]mdl.Employee{1: {ID: 1, Name: "boss", ManagerID: 0}})
+				if err != nil {
+					return err
+				}
+
+				q = geq.SelectFrom(d.Employees).JoinRels(d.Employees.Manager, d.Employees.Reports).
+					Where(d.Employees.Reports.T().ID.Eq(4))
+				err = assertQuery(q, sjoin(
+					"SELECT employees.id, employees.name, employees.manager_id FROM employees",
+					"INNER JOIN employees AS employees_manager ON employees.manager_id = employees_manager.id",
+					"INNER JOIN employees AS employees_reports ON employees.id = employees_reports.manager_id",
+					"WHERE employees_reports.id = ?",
+				), 4)
+				if err != nil {
+					return err
+				}
+				middles, err := q.Load(ctx, db)
+				if err != nil {
+					return err
+				}
+				err = assertEqual(middles, []mdl.Employee{{ID: 2, Name: "emp2", ManagerID: 1}})
+				if err != nil {
+					return err
+				}
+				return nil
+			},
+		},
+		{
+			name: "join nested relationships",
+			data: `
+				INSERT INTO comments (id, post_id, body) VALUES (1, 1, 'comment1'), (2, 1, 'comment2'), (3, 3, 'comment3'), (4, 4, 'comment4');
+			`,
+			run: func(db *sql.Tx) (err error) {
+				var users map[int64]mdl.User
+				var posts map[int64]mdl.Post
+				var comments map[int64]mdl.Comment
+				userPosts := d.Users.Posts
+				postComments := userPosts.T().Comments
+				q := geq.SelectFrom(d.Users).JoinRels(userPosts, postComments).
+					Where(d.Users.ID.In([]int64{1, 2})).
+					OrderBy(postComments.T().ID)
+				err = assertQuery(q, sjoin(
+					"SELECT users.id, users.name FROM users",
+					"INNER JOIN posts AS users_posts ON users.id = users_posts.author_id",
+					"INNER JOIN comments AS users_posts_comments ON users_posts.id = users_posts_comments.post_id",
+					"WHERE users.id IN (?, ?) ORDER BY users_posts_comments.id",
+				), int64(1), int64(2))
+				if err != nil {
+					return err
+				}
+				err = q.WillScan(
+					geq.ToMap(d.Users, d.Users.ID, &users),
+					geq.ToMap(userPosts, userPosts.T().ID, &posts),
+					geq.ToMap(postComments, postComments.T().ID, &comments),
+				).Load(ctx, db)
+				if err != nil {
+					return err
+				}
+				err = assertEqual(users, map[int64]mdl.User{
+					1: {ID: 1, Name: "user1"},
+					2: {ID: 2, Name: "user2"},
+				})
+				if err != nil {
+					return err
+				}
+				err = assertEqual(posts, map[int64]mdl.Post{
+					1: {ID: 1, AuthorID: 1, Title: "user1-post1"},
+					3: {ID: 3, AuthorID: 2, Title: "user2-post1"},
+				})
+				if err != nil {
+					return err
+				}
+				err = assertEqual(comments, map[int64]mdl.Comment{
+					1: {ID: 1, PostID: 1, Body: "comment1"},
+					2: {ID: 2, PostID: 1, Body: "comment2"},
+					3: {ID: 3, PostID: 3, Body: "comment3"},
+				})
+				if err != nil {
+					return err
+				}
+				return nil
+			},
+		},
+		{
+			name: "find records by primary keys",
+			run: func(db *sql.Tx) (err error) {
+				user, err := geq.FindByPK(ctx, db, d.Users, 2)
+				if err != nil {
+					return err
+				}
+				err = assertEqual(user, mdl.User{ID: 2, Name: "user2"})
+				if err != nil {
+					return err
+				}
+
+				_, err = geq.FindByPK(ctx, db, d.Users, 100)
+				if !errors.Is(err, geq.ErrNoRows) || !errors.Is(err, sql.ErrNoRows) {
+					return fmt.Errorf("unexpected error: %v", err)
+				}
+
+				users, err := geq.FindAllByPKs(ctx, db, d.Users, []int64{1, 3, 100})
+				if err != nil {
+					return err
+				}
+				err = assertEqual(users, map[int64]mdl.User{
+					1: {ID: 1, Name: "user1"},
+					3: {ID: 3, Name: "user3"},
+				})
+				if err != nil {
+					return err
+				}
+				return nil
+			},
+		},
+		{
+			name: "load single row",
+			run: func(db *sql.Tx) (err error) {
+				post, err := geq.SelectFrom(d.Posts).Where(d.Posts.AuthorID.Eq(3)).OrderBy(d.Posts.ID.Desc()).LoadOne(ctx, db)
+				if err != nil {
+					return err
+				}
+				err = assertEqual(post, mdl.Post{ID: 6, AuthorID: 3, Title: "user3-post3"})
+				if err != nil {
+					return err
+				}
+
+				q := geq.SelectOnly(d.Posts.Title).From(d.Posts).OrderBy(d.Posts.ID)
+				err = assertQuery(q, "SELECT posts.title FROM posts ORDER BY posts.id")
+				if err != nil {
+					return err
+				}
+				rec := &queryRecorder{db: db}
+				title, err := q.LoadFirst(ctx, rec)
+				if err != nil {
+					return err
+				}
+				err = assertEqual(title, "user1-post1")
+				if err != nil {
+					return err
+				}
+				// The identifiers are quoted depending on the dialect of the DB.
+				if len(rec.queries) != 1 || !strings.HasSuffix(rec.queries[0], " LIMIT 1") {
+					return fmt.Errorf("LoadFirst must limit the query: %v", rec.queries)
+				}
+
+				user, err := geq.SelectFrom(d.Users).Where(d.Users.Name.Eq("user2")).LoadExactlyOne(ctx, db)
+				if err != nil {
+					return err
+				}
+				err = assertEqual(user, mdl.User{ID: 2, Name: "user2"})
+				if err != nil {
+					return err
+				}
+
+				_, err = geq.SelectFrom(d.Posts).Where(d.Posts.AuthorID.Eq(1)).LoadExactlyOne(ctx, db)
+				if !errors.Is(err, geq.ErrMultipleRows) {
+					return fmt.Errorf("unexpected error: %v", err)
+				}
+
+				_, err = geq.SelectFrom(d.Posts).Where(d.Posts.AuthorID.Eq(100)).LoadOne(ctx, db)
+				if !errors.Is(err, geq.ErrNoRows) || !errors.Is(err, sql.ErrNoRows) {
+					return fmt.Errorf("unexpected error: %v", err)
+				}
+				return nil
+			},
+		},
+		{
+			name: "count rows",
+			run: func(db *sql.Tx) (err error) {
+				q := geq.SelectFrom(d.Posts).Where(d.Posts.AuthorID.Neq(2)).OrderBy(d.Posts.ID).Limit(2)
+				err = assertQuery(q.CountQuery(), "SELECT COUNT(*) FROM posts WHERE posts.author_id <> ?", 2)
+				if err != nil {
+					return err
+				}
+				cnt, err := q.Count(ctx, db)
+				if err != nil {
+					return err
+				}
+				err = assertEqual(cnt, int64(5))
+				if err != nil {
+					return err
+				}
+
+				q2 := geq.SelectOnly(d.Posts.AuthorID).From(d.Posts).Distinct().OrderBy(d.Posts.AuthorID)
+				err = assertQuery(q2.CountQuery(), sjoin(
+					"SELECT COUNT(*) FROM (SELECT DISTINCT posts.author_id FROM posts) AS sub",
+				))
+				if err != nil {
+					return err
+				}
+				cnt, err = q2.Count(ctx, db)
+				if err != nil {
+					return err
+				}
+				err = assertEqual(cnt, int64(3))
+				if err != nil {
+					return err
+				}
+
+				q3 := geq.SelectOnly(d.Posts.AuthorID).From(d.Posts).GroupBy(d.Posts.AuthorID).Having(geq.Count(d.Posts.ID).Gt(1))
+				err = assertQuery(q3.CountQuery(), sjoin(
+					"SELECT COUNT(*) FROM (SELECT posts.author_id FROM posts",
+					"GROUP BY posts.author_id HAVING COUNT(posts.id) > ?) AS sub",
+				), 1)
+				if err != nil {
+					return err
+				}
+				cnt, err = q3.Count(ctx, db)
+				if err != nil {
+					return err
+				}
+				err = assertEqual(cnt, int64(2))
+				if err != nil {
+					return err
+				}
+				return nil
+			},
+		},
+		{
+			name: "check existence of rows",
+			run: func(db *sql.Tx) (err error) {
+				q := geq.SelectFrom(d.Posts).Where(d.Posts.AuthorID.Eq(2))
+				err = assertQuery(q.ExistsQuery(), sjoin(
+					"SELECT EXISTS(SELECT posts.id, posts.author_id, posts.title FROM posts WHERE posts.author_id = ?)",
+				), 2)
+				if err != nil {
+					return err
+				}
+				exists, err := q.Exists(ctx, db)
+				if err != nil {
+					return err
+				}
+				err = assertEqual(exists, true)
+				if err != nil {
+					return err
+				}
+				exists, err = geq.SelectFrom(d.Posts).Where(d.Posts.AuthorID.Eq(100)).Exists(ctx, db)
+				if err != nil {
+					return err
+				}
+				err = assertEqual(exists, false)
+				if err != nil {
+					return err
+				}
+				return nil
+			},
+		},
+		{
+			name: "pluck a column",
+			run: func(db *sql.Tx) (err error) {
+				q := geq.Pluck(geq.SelectFrom(d.Posts).Where(d.Posts.AuthorID.Eq(3)).OrderBy(d.Posts.ID), d.Posts.Title)
+				err = assertQuery(q, "SELECT posts.title FROM posts WHERE posts.author_id = ? ORDER BY posts.id", 3)
+				if err != nil {
+					return err
+				}
+				titles, err := q.Load(ctx, db)
+				if err != nil {
+					return err
+				}
+				err = assertEqual(titles, []string{"user3-post1", "user3-post2", "user3-post3"})
+				if err != nil {
+					return err
+				}
+				return nil
+			},
+		},
+		{
+			name: "paginate by keyset",
+			run: func(db *sql.Tx) (err error) {
+				q := geq.SelectFrom(d.Posts)
+				orders := []geq.Orderer{d.Posts.AuthorID.Desc(), d.Posts.ID.Desc()}
+
+				page1, err := geq.Paginate(q, 2, "", orders...).Load(ctx, db)
+				if err != nil {
+					return err
+				}
+				err = assertEqual(page1.Rows, []mdl.Post{
+					{ID: 6, AuthorID: 3, Title: "user3-post3"},
+					{ID: 5, AuthorID: 3, Title: "user3-post2"},
+				})
+				if err != nil {
+					return err
+				}
+				if page1.NextCursor == "" || page1.PrevCursor != "" {
+					return fmt.Errorf("unexpected cursors of page1: %q, %q", page1.NextCursor, page1.PrevCursor)
+				}
+
+				pq, err := geq.Paginate(q, 2, page1.NextCursor, orders...).Query()
+				if err != nil {
+					return err
+				}
+				err = assertQuery(pq, sjoin(
+					"SELECT posts.id, posts.author_id, posts.title FROM posts",
+					"WHERE (posts.author_id, posts.id) < (?, ?)",
+					"ORDER BY posts.author_id DESC, posts.id DESC LIMIT 3",
+				), int64(3), int64(5))
+				if err != nil {
+					return err
+				}
+				page2, err := geq.Paginate(q, 2, page1.NextCursor, orders...).Load(ctx, db)
+				if err != nil {
+					return err
+				}
+				err = assertEqual(page2.Rows, []mdl.Post{
+					{ID: 4, AuthorID: 3, Title: "user3-post1"},
+					{ID: 3, AuthorID: 2, Title: "user2-post1"},
+				})
+				if err != nil {
+					return err
+				}
+
+				page3, err := geq.Paginate(q, 2, page2.NextCursor, orders...).Load(ctx, db)
+				if err != nil {
+					return err
+				}
+				err = assertEqual(page3.Rows, []mdl.Post{
+					{ID: 2, AuthorID: 1, Title: "user1-post2"},
+					{ID: 1, AuthorID: 1, Title: "user1-post1"},
+				})
+				if err != nil {
+					return err
+				}
+				if page3.NextCursor != "" || page3.PrevCursor == "" {
+					return fmt.Errorf("unexpected cursors of page3: %q, %q", page3.NextCursor, page3.PrevCursor)
+				}
+
+				prev, err := geq.Paginate(q, 2, page3.PrevCursor, orders...).Load(ctx, db)
+				if err != nil {
+					return err
+				}
+				err = assertEqual(prev, page2)
+				if err != nil {
+					return err
+				}
+				return nil
+			},
+		},
+		{
+			name: "multiple having conditions",
+			run: func(db *sql.Tx) (err error) {
+				q := geq.SelectOnly(d.Posts.AuthorID).From(d.Posts).
+					GroupBy(d.Posts.AuthorID).
+					Having(geq.Count(d.Posts.ID).Gt(1), geq.Max(d.Posts.ID).Lt(6).Or(geq.Min(d.Posts.ID).Eq(4))).
+					OrderBy(d.Posts.AuthorID)
+				err = assertQuery(q, sjoin(
+					"SELECT posts.author_id FROM posts GROUP BY posts.author_id",
+					"HAVING COUNT(posts.id) > ? AND (MAX(posts.id) < ? OR MIN(posts.id) = ?)",
+					"ORDER BY posts.author_id",
+				), 1, 6, 4)
+				if err != nil {
+					return err
+				}
+				ids, err := q.Load(ctx, db)
+				if err != nil {
+					return err
+				}
+				err = assertEqual(ids, []int64{1, 3})
+				if err != nil {
+					return err
+				}
+				return nil
+			},
+		},
+		{
+			name: "delete records in batches",
+			run: func(db *sql.Tx) (err error) {
+				q := geq.DeleteFrom(d.Posts).Where(d.Posts.AuthorID.Eq(int64(3)))
+				deleted, err := geq.DeleteInBatches(ctx, db, q, 2)
+				if err != nil {
+					return err
+				}
+				err = assertEqual(deleted, int64(3))
+				if err != nil {
+					return err
+				}
+				ids, err := geq.SelectOnly(d.Posts.ID).From(d.Posts).OrderBy(d.Posts.ID).Load(ctx, db)
+				if err != nil {
+					return err
+				}
+				return assertEqual(ids, []int64{1, 2, 3})
+			},
+		},
+		{
+			name: "update records from rows",
+			run: func(db *sql.Tx) (err error) {
+				post, err := geq.FindByPK(ctx, db, d.Posts, 1)
+				if err != nil {
+					return err
+				}
+				changed := post
+				changed.Title = "changed"
+
+				q := geq.Update(d.Posts).SetRow(changed)
+				err = assertQuery(q, "UPDATE posts SET author_id = ?, title = ? WHERE posts.id = ?", int64(1), "changed", int64(1))
+				if err != nil {
+					return err
+				}
+
+				q = geq.Update(d.Posts).SetChanged(post, changed)
+				err = assertQuery(q, "UPDATE posts SET title = ? WHERE posts.id = ?", "changed", int64(1))
+				if err != nil {
+					return err
+				}
+				ret, err := q.Exec(ctx, db)
+				if err != nil {
+					return err
+				}
+				n, err := ret.RowsAffected()
+				if err != nil {
+					return err
+				}
+				err = assertEqual(n, int64(1))
+				if err != nil {
+					return err
+				}
+
+				ret, err = geq.Update(d.Posts).SetChanged(changed, changed).Exec(ctx, db)
+				if err != nil {
+					return err
+				}
+				n, err = ret.RowsAffected()
+				if err != nil {
+					return err
+				}
+				err = assertEqual(n, int64(0))
+				if err != nil {
+					return err
+				}
+
+				_, err = geq.Update(d.Posts).SetRow(mdl.User{ID: 1}).Build()
+				if err == nil {
+					return errors.New("row of another table must be rejected")
+				}
+
+				got, err := geq.FindByPK(ctx, db, d.Posts, 1)
+				if err != nil {
+					return err
+				}
+				return assertEqual(got, mdl.Post{ID: 1, AuthorID: 1, Title: "changed"})
+			},
+		},
+		{
+			name: "update records with version check",
+			run: func(db *sql.Tx) (err error) {
+				doc, err := geq.FindByPK(ctx, db, d.Documents, 1)
+				if err != nil {
+					return err
+				}
+				changed := doc
+				changed.Title = "changed"
+
+				q := geq.Update(d.Documents).SetChanged(doc, changed)
+				err = assertQuery(q, sjoin(
+					"UPDATE documents SET title = ?, version = documents.version + ?",
+					"WHERE documents.id = ? AND documents.version = ?",
+				), "changed", 1, int64(1), int64(1))
+				if err != nil {
+					return err
+				}
+				_, err = q.Exec(ctx, db)
+				if err != nil {
+					return err
+				}
+
+				// The version has been incremented by the previous update.
+				_, err = geq.Update(d.Documents).SetRow(changed).Exec(ctx, db)
+				if !errors.Is(err, geq.ErrStaleRecord) {
+					return fmt.Errorf("unexpected error: %v", err)
+				}
+
+				got, err := geq.FindByPK(ctx, db, d.Documents, 1)
+				if err != nil {
+					return err
+				}
+				return assertEqual(got, mdl.Document{ID: 1, OwnerID: 1, Title: "changed", Version: 2})
+			},
+		},
+		{
+			name: "update version only rows",
+			run: func(db *sql.Tx) (err error) {
+				now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+				geq.SetClock(func() time.Time { return now })
+				defer geq.SetClock(time.Now)
+
+				_, err = geq.InsertInto(d.Counters).Values(d.Counters.ID.Set(1), d.Counters.Version.Set(1)).Exec(ctx, db)
+				if err != nil {
+					return err
+				}
+				counter := mdl.Counter{ID: 1, Version: 1}
+
+				// The version is incremented even if there are no other columns to update.
+				q := geq.Update(d.Counters).SetRow(counter)
+				err = assertQuery(q, sjoin(
+					"UPDATE counters SET version = counters.version + ?, updated_at = ?",
+					"WHERE counters.id = ? AND counters.version = ?",
+				), 1, now, int64(1), int64(1))
+				if err != nil {
+					return err
+				}
+				_, err = q.Exec(ctx, db)
+				if err != nil {
+					return err
+				}
+				_, err = q.Exec(ctx, db)
+				if !errors.Is(err, geq.ErrStaleRecord) {
+					return fmt.Errorf("unexpected error: %v", err)
+				}
+
+				got, err := geq.SelectOnly(d.Counters.Version).From(d.Counters).LoadOne(ctx, db)
+				if err != nil {
+					return err
+				}
+				return assertEqual(got, int64(2))
+			},
+		},
+		{
+			name: "exclude soft deleted records",
+			run: func(db *sql.Tx) (err error) {
+				q := geq.SelectOnly(d.Documents.ID).From(d.Documents).OrderBy(d.Documents.ID)
+				err = assertQuery(q, "SELECT documents.id FROM documents WHERE documents.deleted_at IS NULL ORDER BY documents.id")
+				if err != nil {
+					return err
+				}
+				ids, err := q.Load(ctx, db)
+				if err != nil {
+					return err
+				}
+				err = assertEqual(ids, []int64{1, 3})
+				if err != nil {
+					return err
+				}
+				ids, err = q.WithDeleted().Load(ctx, db)
+				if err != nil {
+					return err
+				}
+				err = assertEqual(ids, []int64{1, 2, 3})
+				if err != nil {
+					return err
+				}
+
+				jq := geq.SelectOnly(d.Users.Documents.T().ID).From(d.Users).JoinRels(d.Users.Documents).Where(d.Users.ID.Eq(1))
+				err = assertQuery(jq, sjoin(
+					"SELECT users_documents.id FROM users",
+					"INNER JOIN documents AS users_documents",
+					"ON users.id = users_documents.owner_id AND users_documents.deleted_at IS NULL",
+					"WHERE users.id = ?",
+				), 1)
+				if err != nil {
+					return err
+				}
+				ids, err = jq.Load(ctx, db)
+				if err != nil {
+					return err
+				}
+				err = assertEqual(ids, []int64{1})
+				if err != nil {
+					return err
+				}
+
+				users := []mdl.User{{ID: 1, Name: "user1"}}
+				vq := geq.SelectVia(users, d.Documents, d.Documents.Owner)
+				err = assertQuery(vq, sjoin(
+					"SELECT documents.id, documents.owner_id, documents.title, documents.version, documents.deleted_at",
+					"FROM documents WHERE documents.owner_id IN (?) AND documents.deleted_at IS NULL",
+				), int64(1))
+				if err != nil {
+					return err
+				}
+				docMap, err := geq.LoadRelated(ctx, db, users, d.Users.Documents)
+				if err != nil {
+					return err
+				}
+				return assertEqual(len(docMap[1]), 1)
+			},
+		},
+		{
+			name: "soft delete records",
+			run: func(db *sql.Tx) (err error) {
+				now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+				geq.SetClock(func() time.Time { return now })
+				defer geq.SetClock(time.Now)
+
+				q := geq.DeleteFrom(d.Documents).Where(d.Documents.ID.Eq(1))
+				err = assertQuery(q, sjoin(
+					"UPDATE documents SET deleted_at = ?",
+					"WHERE documents.id = ? AND documents.deleted_at IS NULL",
+				), now, 1)
+				if err != nil {
+					return err
+				}
+				_, err = q.Exec(ctx, db)
+				if err != nil {
+					return err
+				}
+
+				hq := geq.DeleteFrom(d.Documents).Where(d.Documents.ID.Eq(2)).HardDelete()
+				err = assertQuery(hq, "DELETE FROM documents WHERE documents.id = ?", 2)
+				if err != nil {
+					return err
+				}
+				_, err = hq.Exec(ctx, db)
+				if err != nil {
+					return err
+				}
+
+				docs, err := geq.SelectFrom(d.Documents).WithDeleted().OrderBy(d.Documents.ID).Load(ctx, db)
+				if err != nil {
+					return err
+				}
+				err = assertEqual(len(docs), 2)
+				if err != nil {
+					return err
+				}
+				if !docs[0].DeletedAt.Valid || docs[1].DeletedAt.Valid {
+					return fmt.Errorf("unexpected deletion: %v", docs)
+				}
+				return nil
+			},
+		},
+		{
+			name: "fill automatic timestamps",
+			run: func(db *sql.Tx) (err error) {
+				created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+				updated := created.Add(time.Hour)
+				geq.SetClock(func() time.Time { return created })
+				defer geq.SetClock(time.Now)
+
+				iq := geq.InsertInto(d.Notes).Values(d.Notes.ID.Set(1), d.Notes.Body.Set("a"))
+				err = assertQuery(iq, "INSERT INTO notes (id, body, created_at, updated_at) VALUES (?, ?, ?, ?)", int64(1), "a", created, created)
+				if err != nil {
+					return err
+				}
+				_, err = iq.Exec(ctx, db)
+				if err != nil {
+					return err
+				}
+
+				geq.SetClock(func() time.Time { return updated })
+				uq := geq.Update(d.Notes).Set(d.Notes.Body.Set("b")).Where(d.Notes.ID.Eq(1))
+				err = assertQuery(uq, "UPDATE notes SET body = ?, updated_at = ? WHERE notes.id = ?", "b", updated, 1)
+				if err != nil {
+					return err
+				}
+				_, err = uq.Exec(ctx, db)
+				if err != nil {
+					return err
+				}
+
+				explicit := created.Add(-time.Hour)
+				uq = geq.Update(d.Notes).Set(d.Notes.UpdatedAt.Set(explicit)).Where(d.Notes.ID.Eq(1))
+				err = assertQuery(uq, "UPDATE notes SET updated_at = ? WHERE notes.id = ?", explicit, 1)
+				if err != nil {
+					return err
+				}
+
+				note, err := geq.FindByPK(ctx, db, d.Notes, 1)
+				if err != nil {
+					return err
+				}
+				if !note.CreatedAt.Equal(created) || !note.UpdatedAt.Equal(updated) {
+					return fmt.Errorf("unexpected timestamps: %v", note)
+				}
+				return nil
+			},
+		},
 	})
 }
+
+// insertGroups inserts groups by geq to quote the table name, which is reserved in MySQL.
+func insertGroups(ctx context.Context, db *sql.Tx) error {
+	_, err := geq.InsertInto(d.Groups).ValueMaps(
+		geq.ValueMap{d.Groups.ID: 1, d.Groups.Name: "group1"},
+		geq.ValueMap{d.Groups.ID: 2, d.Groups.Name: "group2"},
+	).Exec(ctx, db)
+	return err
+}
@@ -6,11 +6,46 @@ type GeqTables struct {
 	Users        mdl.User
 	Posts        mdl.Post
 	Transactions mdl.Transaction
+	Invoices     mdl.Invoice `geq:"schema=billing"`
+	Orders       mdl.Order
+	OrderItems   mdl.OrderItem
+	Groups       mdl.Group
+	UserGroups   mdl.UserGroup
+	Employees    mdl.Employee
+	Comments     mdl.Comment
+	Documents    mdl.Document
+	Notes        mdl.Note
+	Counters     mdl.Counter
 }
 
 type GeqRelationships struct {
+	Users struct {
+		Groups    mdl.Group    `geq:"Users.ID = UserGroups.UserID, UserGroups.GroupID = Groups.ID"`
+		Posts     mdl.Post     `geq:"Users.ID = Posts.AuthorID"`
+		Documents mdl.Document `geq:"Users.ID = Documents.OwnerID"`
+	}
 	Posts struct {
-		Author mdl.User `geq:"Posts.AuthorID = Users.ID"`
+		Author   mdl.User    `geq:"Posts.AuthorID = Users.ID"`
+		Comments mdl.Comment `geq:"Posts.ID = Comments.PostID"`
+	}
+	Invoices struct {
+		User mdl.User `geq:"Invoices.UserID = Users.ID"`
+	}
+	Orders struct {
+		Items mdl.OrderItem `geq:"Orders.TenantID = OrderItems.TenantID AND Orders.ID = OrderItems.OrderID AND OrderItems.DeletedAt IS NULL"`
+	}
+	OrderItems struct {
+		Order mdl.Order `geq:"OrderItems.TenantID = Orders.TenantID AND OrderItems.OrderID = Orders.ID"`
+	}
+	Groups struct {
+		Users mdl.User `geq:"Groups.ID = UserGroups.GroupID, UserGroups.UserID = Users.ID"`
+	}
+	Documents struct {
+		Owner mdl.User `geq:"Documents.OwnerID = Users.ID"`
+	}
+	Employees struct {
+		Manager mdl.Employee `geq:"Employees.ManagerID = Employees.ID"`
+		Reports mdl.Employee `geq:"Employees.ID = Employees.ManagerID"`
 	}
 }
 
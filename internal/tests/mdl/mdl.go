@@ -1,6 +1,9 @@
 package mdl
 
-import "time"
+import (
+	"database/sql"
+	"time"
+)
 
 type User struct {
 	ID   int64
@@ -21,6 +24,69 @@ type Transaction struct {
 	CreatedAt   time.Time
 }
 
+type Invoice struct {
+	ID     int64
+	UserID int64
+	Amount int32
+}
+
+type Order struct {
+	TenantID int64 `geq:"pk"`
+	ID       int64 `geq:"pk"`
+	UserID   int64
+}
+
+type OrderItem struct {
+	TenantID  int64 `geq:"pk"`
+	OrderID   int64 `geq:"pk"`
+	ID        int64 `geq:"pk"`
+	Name      string
+	DeletedAt sql.NullTime
+}
+
+type Group struct {
+	ID   int64
+	Name string
+}
+
+type UserGroup struct {
+	UserID  int64
+	GroupID int64
+}
+
+type Employee struct {
+	ID        int64
+	Name      string
+	ManagerID int64
+}
+
+type Comment struct {
+	ID     int64
+	PostID int64
+	Body   string
+}
+
+type Document struct {
+	ID        int64
+	OwnerID   int64
+	Title     string
+	Version   int64        `geq:"version"`
+	DeletedAt sql.NullTime `geq:"softDelete"`
+}
+
+type Note struct {
+	ID        int64
+	Body      string
+	CreatedAt time.Time `geq:"autoCreateTime"`
+	UpdatedAt time.Time `geq:"autoUpdateTime"`
+}
+
+type Counter struct {
+	ID        int64
+	Version   int64     `geq:"version"`
+	UpdatedAt time.Time `geq:"autoUpdateTime"`
+}
+
 type PostStat struct {
 	AuthorID  int64
 	PostCount int64
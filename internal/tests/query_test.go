@@ -0,0 +1,245 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ryym/geq"
+	"github.com/ryym/geq/internal/tests/d"
+	"github.com/ryym/geq/internal/tests/mdl"
+)
+
+func TestImmutableQuery(t *testing.T) {
+	base := geq.SelectFrom(d.Users).Where(d.Users.ID.Gt(0))
+	q1 := base.Where(d.Users.Name.Eq("a")).OrderBy(d.Users.ID).Limit(1)
+	q2 := base.Where(d.Users.Name.Eq("b")).JoinRels(d.Users.Posts)
+
+	err := assertQuery(base, "SELECT users.id, users.name FROM users WHERE users.id > ?", 0)
+	if err != nil {
+		t.Error(err)
+	}
+	err = assertQuery(q1, sjoin(
+		"SELECT users.id, users.name FROM users",
+		"WHERE users.id > ? AND users.name = ? ORDER BY users.id LIMIT 1",
+	), 0, "a")
+	if err != nil {
+		t.Error(err)
+	}
+	err = assertQuery(q2, sjoin(
+		"SELECT users.id, users.name FROM users",
+		"INNER JOIN posts AS users_posts ON users.id = users_posts.author_id",
+		"WHERE users.id > ? AND users.name = ?",
+	), 0, "b")
+	if err != nil {
+		t.Error(err)
+	}
+
+	cond := d.Users.ID.Eq(1).And(d.Users.Name.Eq("a").Or(d.Users.Name.Eq("b")))
+	for i := 0; i < 2; i++ {
+		err = assertQuery(geq.SelectFrom(d.Users).Where(cond), sjoin(
+			"SELECT users.id, users.name FROM users",
+			"WHERE users.id = ? AND (users.name = ? OR users.name = ?)",
+		), 1, "a", "b")
+		if err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestImmutableUpdateQuery(t *testing.T) {
+	base := geq.Update(d.Users).Set(d.Users.Name.Set("a"))
+	q := base.Where(d.Users.ID.Eq(1))
+	err := assertQuery(base.All(), "UPDATE users SET name = ?", "a")
+	if err != nil {
+		t.Error(err)
+	}
+	err = assertQuery(q, "UPDATE users SET name = ? WHERE users.id = ?", "a", 1)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestConcurrentBuild(t *testing.T) {
+	users := d.NewUsers("u")
+	users.InitRelships()
+	base := geq.SelectFrom(users).Where(users.ID.Gt(0).And(users.Name.Neq("").Or(users.Name.IsNull())))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 50)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			comments := users.Posts.T().Comments
+			q := base.Where(users.Name.Eq(fmt.Sprint(i))).JoinRels(users.Posts, comments).Limit(uint(i + 1))
+			errs <- assertQuery(q, sjoin(
+				"SELECT u.id, u.name FROM users AS u",
+				"INNER JOIN posts AS u_posts ON u.id = u_posts.author_id",
+				"INNER JOIN comments AS u_posts_comments ON u_posts.id = u_posts_comments.post_id",
+				"WHERE u.id > ? AND (u.name <> ? OR u.name IS NULL) AND u.name = ?",
+				fmt.Sprintf("LIMIT %d", i+1),
+			), 0, "", fmt.Sprint(i))
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestConcurrentSetClock(t *testing.T) {
+	defer geq.SetClock(nil)
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			geq.SetClock(func() time.Time { return now })
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := geq.InsertInto(d.Notes).Values(d.Notes.Body.Set("a")).Build()
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	err := assertQuery(geq.Update(d.Notes).Set(d.Notes.Body.Set("b")).All(), "UPDATE notes SET body = ?, updated_at = ?", "b", now)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestConditionalExprs(t *testing.T) {
+	name := ""
+	minID := int64(2)
+
+	q := geq.SelectFrom(d.Users).Where(
+		geq.AndIf(name != "", d.Users.Name.Eq(name)),
+		geq.AndIf(minID > 0, d.Users.ID.Gte(minID)),
+	)
+	err := assertQuery(q, "SELECT users.id, users.name FROM users WHERE users.id >= ?", int64(2))
+	if err != nil {
+		t.Error(err)
+	}
+
+	q = geq.SelectFrom(d.Users).Where(geq.And(), geq.Or(nil, nil)).WhereIf(name != "", d.Users.Name.Eq(name))
+	err = assertQuery(q, "SELECT users.id, users.name FROM users")
+	if err != nil {
+		t.Error(err)
+	}
+
+	q = geq.SelectFrom(d.Users).Where(
+		geq.Or(d.Users.ID.Eq(1), nil, d.Users.ID.Eq(2)),
+		geq.And(geq.Or(d.Users.Name.Eq("a"), d.Users.Name.Eq("b")), d.Users.ID.Lt(5)),
+	)
+	err = assertQuery(q, sjoin(
+		"SELECT users.id, users.name FROM users",
+		"WHERE (users.id = ? OR users.id = ?)",
+		"AND (users.name = ? OR users.name = ?) AND users.id < ?",
+	), 1, 2, "a", "b", 5)
+	if err != nil {
+		t.Error(err)
+	}
+
+	del := geq.DeleteFrom(d.Users).Where(geq.AndIf(false, d.Users.ID.Eq(1))).All()
+	err = assertQuery(del, "DELETE FROM users")
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPredicatePrecedence(t *testing.T) {
+	orCond := d.Posts.AuthorID.Eq(1).Or(d.Posts.AuthorID.Eq(2))
+	titleCond := d.Posts.Title.Neq("")
+
+	q := geq.SelectOnly(d.Posts.AuthorID).From(d.Posts).
+		InnerJoin(d.Users, d.Users.ID.Eq(d.Posts.AuthorID).And(d.Users.Name.Eq("a").Or(d.Users.Name.Eq("b")))).
+		Where(orCond, titleCond).
+		GroupBy(d.Posts.AuthorID).
+		Having(geq.Count(d.Posts.ID).Gt(1).Or(geq.Max(d.Posts.ID).Eq(9)), geq.Min(d.Posts.ID).Gt(0))
+	err := assertQuery(q, sjoin(
+		"SELECT posts.author_id FROM posts",
+		"INNER JOIN users ON users.id = posts.author_id AND (users.name = ? OR users.name = ?)",
+		"WHERE (posts.author_id = ? OR posts.author_id = ?) AND posts.title <> ?",
+		"GROUP BY posts.author_id",
+		"HAVING (COUNT(posts.id) > ? OR MAX(posts.id) = ?) AND MIN(posts.id) > ?",
+	), "a", "b", 1, 2, "", 1, 9, 0)
+	if err != nil {
+		t.Error(err)
+	}
+
+	upd := geq.Update(d.Posts).Set(d.Posts.Title.Set("t")).Where(titleCond, orCond)
+	err = assertQuery(upd, sjoin(
+		"UPDATE posts SET title = ?",
+		"WHERE posts.title <> ? AND (posts.author_id = ? OR posts.author_id = ?)",
+	), "t", "", 1, 2)
+	if err != nil {
+		t.Error(err)
+	}
+
+	del := geq.DeleteFrom(d.Posts).Where(orCond, titleCond)
+	err = assertQuery(del, sjoin(
+		"DELETE FROM posts",
+		"WHERE (posts.author_id = ? OR posts.author_id = ?) AND posts.title <> ?",
+	), 1, 2, "")
+	if err != nil {
+		t.Error(err)
+	}
+
+	_, err = geq.SelectFrom(d.Posts).InnerJoin(d.Users, geq.And()).Build()
+	if err == nil {
+		t.Error("empty join condition must be an error")
+	}
+}
+
+func TestModifyAllGuard(t *testing.T) {
+	for i, q := range []geq.AnyQuery{
+		geq.Update(d.Users).Set(d.Users.Name.Set("a")),
+		geq.Update(d.Users).Set(d.Users.Name.Set("a")).Where(geq.AndIf(false, d.Users.ID.Eq(1))),
+		geq.DeleteFrom(d.Users),
+		geq.DeleteFrom(d.Users).Where(geq.And()),
+	} {
+		_, err := q.Build()
+		if err == nil {
+			t.Errorf("[%d] query without WHERE must not be built", i)
+		}
+	}
+
+	err := assertQuery(geq.Update(d.Users).Set(d.Users.Name.Set("a")).All(), "UPDATE users SET name = ?", "a")
+	if err != nil {
+		t.Error(err)
+	}
+	err = assertQuery(geq.DeleteFrom(d.Users).All(), "DELETE FROM users")
+	if err != nil {
+		t.Error(err)
+	}
+
+	_, err = geq.DeleteInBatches(context.Background(), nil, geq.DeleteFrom(d.Users), 10)
+	if err == nil {
+		t.Error("DeleteInBatches without WHERE must fail")
+	}
+
+	// The query is validated even if it has nothing to change.
+	user := mdl.User{ID: 1, Name: "a"}
+	_, err = geq.Update(d.Users).SetChanged(user, user).Exec(context.Background(), nil)
+	if err != nil {
+		t.Error(err)
+	}
+	_, err = geq.Update(d.Users).SetChanged(user, user).
+		InnerJoin(d.Posts, d.Posts.AuthorID.Eq(d.Users.ID)).
+		Exec(context.Background(), nil)
+	if err == nil {
+		t.Error("unchanged update with unsupported JOIN must fail")
+	}
+}
@@ -22,6 +22,75 @@ CREATE TABLE transactions (
   description varchar(256) NOT NULL DEFAULT '',
   created_at datetime NOT NULL DEFAULT NOW()
 );
+
+DROP TABLE IF EXISTS orders;
+CREATE TABLE orders (
+  tenant_id int unsigned NOT NULL,
+  id int unsigned NOT NULL,
+  user_id int unsigned NOT NULL,
+  PRIMARY KEY (tenant_id, id)
+);
+
+DROP TABLE IF EXISTS order_items;
+CREATE TABLE order_items (
+  tenant_id int unsigned NOT NULL,
+  order_id int unsigned NOT NULL,
+  id int unsigned NOT NULL,
+  name varchar(128) NOT NULL,
+  deleted_at datetime,
+  PRIMARY KEY (tenant_id, order_id, id)
+);
+
+DROP TABLE IF EXISTS ` + "`groups`" + `;
+CREATE TABLE ` + "`groups`" + ` (
+  id int unsigned NOT NULL PRIMARY KEY AUTO_INCREMENT,
+  name varchar(128) NOT NULL
+);
+
+DROP TABLE IF EXISTS user_groups;
+CREATE TABLE user_groups (
+  user_id int unsigned NOT NULL,
+  group_id int unsigned NOT NULL,
+  PRIMARY KEY (user_id, group_id)
+);
+
+DROP TABLE IF EXISTS employees;
+CREATE TABLE employees (
+  id int unsigned NOT NULL PRIMARY KEY AUTO_INCREMENT,
+  name varchar(128) NOT NULL,
+  manager_id int unsigned NOT NULL
+);
+
+DROP TABLE IF EXISTS comments;
+CREATE TABLE comments (
+  id int unsigned NOT NULL PRIMARY KEY AUTO_INCREMENT,
+  post_id int unsigned NOT NULL,
+  body varchar(128) NOT NULL
+);
+
+DROP TABLE IF EXISTS documents;
+CREATE TABLE documents (
+  id int unsigned NOT NULL PRIMARY KEY AUTO_INCREMENT,
+  owner_id int unsigned NOT NULL,
+  title varchar(128) NOT NULL,
+  version int NOT NULL,
+  deleted_at datetime
+);
+
+DROP TABLE IF EXISTS notes;
+CREATE TABLE notes (
+  id int unsigned NOT NULL PRIMARY KEY AUTO_INCREMENT,
+  body varchar(128) NOT NULL,
+  created_at datetime NOT NULL,
+  updated_at datetime NOT NULL
+);
+
+DROP TABLE IF EXISTS counters;
+CREATE TABLE counters (
+  id int unsigned NOT NULL PRIMARY KEY AUTO_INCREMENT,
+  version int NOT NULL,
+  updated_at datetime NOT NULL
+);
 `
 
 const initPostgreSQL = `
@@ -46,6 +115,75 @@ CREATE TABLE transactions (
   description varchar(256) NOT NULL DEFAULT '',
   created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
 );
+
+DROP TABLE IF EXISTS orders;
+CREATE TABLE orders (
+  tenant_id int NOT NULL,
+  id int NOT NULL,
+  user_id int NOT NULL,
+  PRIMARY KEY (tenant_id, id)
+);
+
+DROP TABLE IF EXISTS order_items;
+CREATE TABLE order_items (
+  tenant_id int NOT NULL,
+  order_id int NOT NULL,
+  id int NOT NULL,
+  name varchar(128) NOT NULL,
+  deleted_at timestamp,
+  PRIMARY KEY (tenant_id, order_id, id)
+);
+
+DROP TABLE IF EXISTS groups;
+CREATE TABLE groups (
+  id serial NOT NULL,
+  name varchar(128) NOT NULL
+);
+
+DROP TABLE IF EXISTS user_groups;
+CREATE TABLE user_groups (
+  user_id int NOT NULL,
+  group_id int NOT NULL,
+  PRIMARY KEY (user_id, group_id)
+);
+
+DROP TABLE IF EXISTS employees;
+CREATE TABLE employees (
+  id serial NOT NULL,
+  name varchar(128) NOT NULL,
+  manager_id int NOT NULL
+);
+
+DROP TABLE IF EXISTS comments;
+CREATE TABLE comments (
+  id serial NOT NULL,
+  post_id int NOT NULL,
+  body varchar(128) NOT NULL
+);
+
+DROP TABLE IF EXISTS documents;
+CREATE TABLE documents (
+  id serial NOT NULL,
+  owner_id int NOT NULL,
+  title varchar(128) NOT NULL,
+  version int NOT NULL,
+  deleted_at timestamp
+);
+
+DROP TABLE IF EXISTS notes;
+CREATE TABLE notes (
+  id serial NOT NULL,
+  body varchar(128) NOT NULL,
+  created_at timestamp NOT NULL,
+  updated_at timestamp NOT NULL
+);
+
+DROP TABLE IF EXISTS counters;
+CREATE TABLE counters (
+  id serial NOT NULL,
+  version int NOT NULL,
+  updated_at timestamp NOT NULL
+);
 `
 
 const fixtureSQL = `
@@ -57,4 +195,8 @@ INSERT INTO posts (id, author_id, title) VALUES
   (4, 3, 'user3-post1'),
   (5, 3, 'user3-post2'),
   (6, 3, 'user3-post3');
+INSERT INTO documents (id, owner_id, title, version, deleted_at) VALUES
+  (1, 1, 'doc1', 1, NULL),
+  (2, 1, 'doc2', 1, '2024-01-01 00:00:00'),
+  (3, 2, 'doc3', 1, NULL);
 `
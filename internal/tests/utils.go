@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"strings"
@@ -49,6 +50,17 @@ func runTestCase(t *testing.T, db *sql.DB, idx int, c testCase) {
 	}
 }
 
+// queryRecorder records the queries run through it.
+type queryRecorder struct {
+	db      geq.QueryRunner
+	queries []string
+}
+
+func (r *queryRecorder) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	r.queries = append(r.queries, query)
+	return r.db.QueryContext(ctx, query, args...)
+}
+
 func assertEqual[V any](got, want V) error {
 	diff := cmp.Diff(want, got)
 	if diff != "" {
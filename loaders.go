@@ -2,6 +2,7 @@ package geq
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 )
 
@@ -34,6 +35,10 @@ func (ms *MultiScanLoader[Q]) Load(ctx context.Context, db QueryRunner) (err err
 		if err != nil {
 			return err
 		}
+		err = rescanRow(rows, ms.scanners, ptrGroups)
+		if err != nil {
+			return err
+		}
 		for i, s := range ms.scanners {
 			s.AfterEachScan(ptrGroups[i])
 		}
@@ -110,3 +115,28 @@ func loadBySingleScanner[Q any](ctx context.Context, db QueryRunner, s RowsScann
 	}
 	return nil
 }
+
+// rescanRow scans the current row again if some scanners require it.
+// Scanning a row multiple times is allowed by database/sql.
+func rescanRow(rows *sql.Rows, scanners []RowsScanner, ptrGroups [][]any) (err error) {
+	rescan := false
+	for i, s := range scanners {
+		rs, ok := s.(rowsRescanner)
+		if !ok {
+			continue
+		}
+		ptrs, ok := rs.rescanPtrs(ptrGroups[i])
+		if ok {
+			ptrGroups[i] = ptrs
+			rescan = true
+		}
+	}
+	if !rescan {
+		return nil
+	}
+	allPtrs := make([]any, 0)
+	for _, ptrs := range ptrGroups {
+		allPtrs = append(allPtrs, ptrs...)
+	}
+	return rows.Scan(allPtrs...)
+}
@@ -0,0 +1,277 @@
+package geq
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Page is a result of keyset pagination.
+type Page[R any] struct {
+	Rows []R
+
+	// NextCursor is a cursor to load the next page. It is empty if there are no more rows.
+	NextCursor string
+
+	// PrevCursor is a cursor to load the previous page. It is empty if this is the first page.
+	PrevCursor string
+}
+
+type PageLoader[R any] struct {
+	query  *Query[R]
+	limit  uint
+	cursor string
+	orders []Orderer
+}
+
+// Paginate returns a loader of keyset pagination. Rows are sorted by the given orders,
+// which must be columns in the selections and must identify each row uniquely as a whole.
+// Specify an empty cursor to load the first page.
+func Paginate[R any](q *Query[R], limit uint, cursor string, orders ...Orderer) *PageLoader[R] {
+	return &PageLoader[R]{query: q, limit: limit, cursor: cursor, orders: orders}
+}
+
+// pageCursor is an opaque cursor encoded in base64 JSON.
+type pageCursor struct {
+	Prev   bool              `json:"p,omitempty"`
+	Values []json.RawMessage `json:"v"`
+}
+
+type cursorValueDecoder interface {
+	decodeCursorValue(raw json.RawMessage) (any, error)
+}
+
+func (c *Column[F]) decodeCursorValue(raw json.RawMessage) (any, error) {
+	var v F
+	err := json.Unmarshal(raw, &v)
+	return v, err
+}
+
+// Query returns the query to load the page, which fetches one more row than the limit
+// to know whether more rows exist.
+func (l *PageLoader[R]) Query() (*Query[R], error) {
+	q, _, _, err := l.prepare()
+	return q, err
+}
+
+func (l *PageLoader[R]) Load(ctx context.Context, db QueryRunner) (page *Page[R], err error) {
+	pq, colIdxs, cursor, err := l.prepare()
+	if err != nil {
+		return nil, err
+	}
+	backward := cursor != nil && cursor.Prev
+
+	rows, err := pq.Load(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	hasMore := uint(len(rows)) > l.limit
+	if hasMore {
+		rows = rows[:l.limit]
+	}
+	if backward {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+
+	page = &Page[R]{Rows: rows}
+	if len(rows) == 0 {
+		return page, nil
+	}
+	if hasMore || backward {
+		page.NextCursor, err = encodePageCursor(l.query.mapper, &rows[len(rows)-1], colIdxs, false)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if (hasMore && backward) || (cursor != nil && !backward) {
+		page.PrevCursor, err = encodePageCursor(l.query.mapper, &rows[0], colIdxs, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
+
+func (l *PageLoader[R]) prepare() (pq *Query[R], colIdxs []int, cursor *pageCursor, err error) {
+	if l.limit == 0 {
+		return nil, nil, nil, errors.New("[geq.Paginate] limit must be positive")
+	}
+	if len(l.orders) == 0 {
+		return nil, nil, nil, errors.New("[geq.Paginate] no orders")
+	}
+
+	sels := l.query.selections
+	items := make([]orderItem, 0, len(l.orders))
+	colIdxs = make([]int, 0, len(l.orders))
+	for _, o := range l.orders {
+		oi := o.order()
+		idx := selectionIndex(sels[0], sels, oi.expr)
+		if idx < 0 {
+			return nil, nil, nil, errors.New("[geq.Paginate] order column not in selections")
+		}
+		items = append(items, oi)
+		colIdxs = append(colIdxs, idx)
+	}
+
+	if l.cursor != "" {
+		cursor, err = decodePageCursor(l.cursor)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if len(cursor.Values) != len(items) {
+			return nil, nil, nil, errors.New("[geq.Paginate] cursor does not match orders")
+		}
+	}
+
+	// Reverse the orders to load the previous page.
+	if cursor != nil && cursor.Prev {
+		reversed := make([]orderItem, 0, len(items))
+		for _, oi := range items {
+			order := "DESC"
+			if oi.order == "DESC" {
+				order = "ASC"
+			}
+			reversed = append(reversed, orderItem{order: order, expr: oi.expr})
+		}
+		items = reversed
+	}
+
+	pq = l.query.Clone()
+	pq.orders = make([]Orderer, 0, len(items))
+	for _, oi := range items {
+		pq.orders = append(pq.orders, &orderer{item: oi})
+	}
+	pq.limit = l.limit + 1
+	pq.offset = 0
+
+	if cursor != nil {
+		vals := make([]any, 0, len(items))
+		for i, oi := range items {
+			dec, ok := oi.expr.(cursorValueDecoder)
+			if !ok {
+				return nil, nil, nil, errors.New("[geq.Paginate] orders must be columns")
+			}
+			v, err := dec.decodeCursorValue(cursor.Values[i])
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("[geq.Paginate] invalid cursor: %w", err)
+			}
+			vals = append(vals, v)
+		}
+		pq.wheres = append(pq.wheres, implOps(&keysetExpr{items: items, values: vals}))
+	}
+	return pq, colIdxs, cursor, nil
+}
+
+func encodePageCursor[R any](mapper RowMapper[R], row *R, colIdxs []int, prev bool) (string, error) {
+	ptrs := mapper.FieldPtrs(row)
+	c := pageCursor{Prev: prev, Values: make([]json.RawMessage, 0, len(colIdxs))}
+	for _, idx := range colIdxs {
+		v, err := json.Marshal(ptrs[idx])
+		if err != nil {
+			return "", fmt.Errorf("[geq.Paginate] failed to encode cursor: %w", err)
+		}
+		c.Values = append(c.Values, v)
+	}
+	b, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("[geq.Paginate] failed to encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func decodePageCursor(s string) (*pageCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("[geq.Paginate] invalid cursor: %w", err)
+	}
+	var c pageCursor
+	err = json.Unmarshal(b, &c)
+	if err != nil {
+		return nil, fmt.Errorf("[geq.Paginate] invalid cursor: %w", err)
+	}
+	return &c, nil
+}
+
+// keysetExpr is a condition to select rows after the given values in the given orders, such as (a, b) > (?, ?).
+type keysetExpr struct {
+	ops
+	items  []orderItem
+	values []any
+}
+
+func (e *keysetExpr) getPrecedence() int {
+	return prcdValue
+}
+
+func (e *keysetExpr) appendExpr(w *queryWriter, cfg *QueryConfig) {
+	sameOrder := true
+	for _, oi := range e.items {
+		sameOrder = sameOrder && oi.order == e.items[0].order
+	}
+
+	if len(e.items) == 1 {
+		e.compare(e.items[0], e.values[0]).appendExpr(w, cfg)
+		return
+	}
+
+	if sameOrder && rowCompareType(cfg.dialect) == RowCompareTuple {
+		w.Write("(")
+		for i, oi := range e.items {
+			if i > 0 {
+				w.Write(", ")
+			}
+			oi.expr.appendExpr(w, cfg)
+		}
+		w.Write(")")
+		if e.items[0].order == "DESC" {
+			w.Write(" < (")
+		} else {
+			w.Write(" > (")
+		}
+		for i, v := range e.values {
+			if i > 0 {
+				w.Write(", ")
+			}
+			toExpr(v).appendExpr(w, cfg)
+		}
+		w.Write(")")
+		return
+	}
+
+	// Expand the comparison: a > ? OR a = ? AND b > ? OR ...
+	var cond Expr
+	for i, oi := range e.items {
+		var c Expr
+		for j := 0; j < i; j++ {
+			eq := e.items[j].expr.Eq(e.values[j])
+			if c == nil {
+				c = eq
+			} else {
+				c = c.And(eq)
+			}
+		}
+		cmp := e.compare(oi, e.values[i])
+		if c == nil {
+			c = cmp
+		} else {
+			c = c.And(cmp)
+		}
+		if cond == nil {
+			cond = c
+		} else {
+			cond = cond.Or(c)
+		}
+	}
+	Parens(cond).appendExpr(w, cfg)
+}
+
+func (e *keysetExpr) compare(oi orderItem, v any) Expr {
+	if oi.order == "DESC" {
+		return oi.expr.Lt(v)
+	}
+	return oi.expr.Gt(v)
+}
@@ -0,0 +1,41 @@
+package geq
+
+// andAll joins the expressions by AND skipping nil ones.
+// It returns nil if there are no expressions to join.
+func andAll(exprs ...Expr) Expr {
+	return joinExprs("AND", prcdAnd, exprs)
+}
+
+// joinExprs joins the expressions by the logical operator skipping nil ones.
+// Operands with lower precedence than the operator are wrapped by parentheses.
+// It returns nil if there are no expressions to join.
+func joinExprs(op string, precedence int, exprs []Expr) Expr {
+	var joined Expr
+	for _, e := range exprs {
+		if e == nil {
+			continue
+		}
+		if joined == nil {
+			// The left side operand of infixExpr is not wrapped automatically.
+			if e.getPrecedence() < precedence {
+				e = Parens(e)
+			}
+			joined = e
+			continue
+		}
+		joined = implOps(&infixExpr{left: joined, right: e, op: op, precedence: precedence})
+	}
+	return joined
+}
+
+// writeConditions writes the conjunction of the expressions following the keyword such as " WHERE ".
+// It writes nothing and returns false if there are no expressions.
+func writeConditions(w *queryWriter, cfg *QueryConfig, keyword string, exprs []Expr) bool {
+	cond := andAll(exprs...)
+	if cond == nil {
+		return false
+	}
+	w.Write(keyword)
+	cond.appendExpr(w, cfg)
+	return true
+}
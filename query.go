@@ -3,7 +3,9 @@ package geq
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 )
 
@@ -29,8 +31,15 @@ type TableLike interface {
 
 type AnyTable interface {
 	TableLike
+	getSchema() string
 	getTableName() string
+	getAlias() string
 	getColumns() []AnyColumn
+	getPKs() []AnyColumn
+	getVersion() AnyColumn
+	getSoftDelete() AnyColumn
+	getAutoCreateTime() AnyColumn
+	getAutoUpdateTime() AnyColumn
 }
 
 type Table[R any] interface {
@@ -39,108 +48,171 @@ type Table[R any] interface {
 	InitRelships()
 }
 
+// TableWithPK is a table which has a single column primary key.
+type TableWithPK[R any, K comparable] interface {
+	Table[R]
+	PK() *Column[K]
+}
+
 type TableBase struct {
+	schema     string
 	tableName  string
 	alias      string
 	columns    []AnyColumn
 	selections []Selection
+	opts       TableOptions
+}
+
+// TableOptions describes columns which have special roles in a table.
+type TableOptions struct {
+	PKs []AnyColumn
+
+	// Version is a column for optimistic locking.
+	Version AnyColumn
+
+	// SoftDelete is a timestamp column which marks soft deleted rows.
+	SoftDelete AnyColumn
+
+	// AutoCreateTime is a timestamp column set on insert unless specified explicitly.
+	AutoCreateTime AnyColumn
+
+	// AutoUpdateTime is a timestamp column set on insert and update unless specified explicitly.
+	AutoUpdateTime AnyColumn
 }
 
-func NewTableBase(tableName string, alias string, columns []AnyColumn, sels []Selection) *TableBase {
+func NewTableBase(schema, tableName, alias string, columns []AnyColumn, sels []Selection, opts TableOptions) *TableBase {
 	if alias == tableName {
 		alias = ""
 	}
 	return &TableBase{
+		schema:     schema,
 		tableName:  tableName,
 		alias:      alias,
 		columns:    columns,
 		selections: sels,
+		opts:       opts,
 	}
 }
 
-func (t *TableBase) getTableName() string {
-	return t.tableName
+func (t *TableBase) getSchema() string {
+	return t.schema
 }
 
-func (t *TableBase) getColumns() []AnyColumn {
-	return t.columns
+func (t *TableBase) getTableName() string {
+	return t.tableName
 }
 
-func (t *TableBase) Selections() []Selection {
-	return t.selections
+func (t *TableBase) getAlias() string {
+	return t.alias
 }
 
-func (t *TableBase) appendTable(w *queryWriter, cfg *QueryConfig) {
-	w.Write(cfg.dialect.Ident(t.tableName))
-	if t.alias != "" {
-		w.Write(" AS ")
-		w.Write(t.alias)
-	}
+func (t *TableBase) getColumns() []AnyColumn {
+	return t.columns
 }
 
-type Selection interface {
-	getExpr() Expr
-	getAlias() string
+func (t *TableBase) getPKs() []AnyColumn {
+	return t.opts.PKs
 }
 
-type TypedSelection[F any] interface {
-	Selection
+func (t *TableBase) getVersion() AnyColumn {
+	return t.opts.Version
 }
 
-type AnyRelship interface {
-	toJoinClause() joinClause
+func (t *TableBase) getSoftDelete() AnyColumn {
+	return t.opts.SoftDelete
 }
 
-type Relship[T Table[R], R, C any] struct {
-	tableR T
-	colL   *Column[C]
-	colR   *Column[C]
+func (t *TableBase) getAutoCreateTime() AnyColumn {
+	return t.opts.AutoCreateTime
 }
 
-func NewRelship[T Table[R], R, C any](tableR T, colL, colR *Column[C]) *Relship[T, R, C] {
-	return &Relship[T, R, C]{tableR: tableR, colL: colL, colR: colR}
+func (t *TableBase) getAutoUpdateTime() AnyColumn {
+	return t.opts.AutoUpdateTime
 }
 
-func (r *Relship[T, R, C]) Selections() []Selection {
-	return r.tableR.Selections()
+func (t *TableBase) Selections() []Selection {
+	return t.selections
 }
 
-func (r *Relship[T, R, C]) FieldPtrs(row *R) []any {
-	return r.tableR.FieldPtrs(row)
+func (t *TableBase) appendTable(w *queryWriter, cfg *QueryConfig) {
+	w.Write(qualifiedTableName(t, cfg))
+	if t.alias != "" {
+		w.Write(" AS ")
+		w.Write(cfg.dialect.Ident(t.alias))
+	}
 }
 
-func (r *Relship[T, R, C]) appendTable(w *queryWriter, cfg *QueryConfig) {
-	r.tableR.appendTable(w, cfg)
+// qualifiedTableName returns the quoted table name prefixed by its schema if any.
+func qualifiedTableName(t AnyTable, cfg *QueryConfig) string {
+	name := cfg.dialect.Ident(t.getTableName())
+	if schema := t.getSchema(); schema != "" {
+		return cfg.dialect.Ident(schema) + "." + name
+	}
+	return name
+}
+
+func writeJoins(w *queryWriter, cfg *QueryConfig, name string, joins []joinClause) {
+	for _, j := range joins {
+		w.Write(" ")
+		w.Write(j.mode)
+		w.Write(" JOIN ")
+		j.table.appendTable(w, cfg)
+		if j.mode != "CROSS" {
+			if !writeConditions(w, cfg, " ON ", []Expr{j.condition}) {
+				w.AddErr(fmt.Errorf("[%s] join condition empty", name))
+			}
+		}
+	}
 }
 
-func (r *Relship[T, R, C]) T() T {
-	r.tableR.InitRelships()
-	return r.tableR
+// excludeSoftDeleted adds conditions to exclude soft deleted rows of the from table and the joined tables.
+func excludeSoftDeleted(from TableLike, joins []joinClause, wheres []Expr) ([]joinClause, []Expr) {
+	wheres = slices.Clone(wheres)
+	if col := softDeleteColumn(from); col != nil {
+		wheres = append(wheres, col.IsNull())
+	}
+	joins = slices.Clone(joins)
+	for i, j := range joins {
+		col := softDeleteColumn(j.table)
+		switch {
+		case col == nil:
+		case j.mode == "CROSS":
+			wheres = append(wheres, col.IsNull())
+		case andAll(j.condition) != nil:
+			joins[i].condition = andAll(j.condition, col.IsNull())
+		}
+	}
+	return joins, wheres
 }
 
-func (r *Relship[T, R, C]) toJoinClause() joinClause {
-	return joinClause{
-		mode:      "INNER",
-		table:     r.tableR,
-		condition: r.colL.Eq(r.colR),
+func softDeleteColumn(t TableLike) AnyColumn {
+	if at, ok := t.(AnyTable); ok {
+		return at.getSoftDelete()
 	}
+	return nil
 }
 
-func (r *Relship[T, R, C]) In(recs []R) Expr {
-	sels := r.tableR.Selections()
-	colIdx := selectionIndex(sels[0], sels, r.colR)
-	if colIdx < 0 {
-		panic("right table column not in selections")
+func writeOrders(w *queryWriter, cfg *QueryConfig, orders []Orderer) {
+	w.Write(" ORDER BY ")
+	for i, o := range orders {
+		if i > 0 {
+			w.Write(", ")
+		}
+		oi := o.order()
+		oi.expr.appendExpr(w, cfg)
+		if oi.order == "DESC" {
+			w.Write(" DESC")
+		}
 	}
+}
 
-	vals := make([]C, 0, len(recs))
-	for _, rec := range recs {
-		ptrs := r.tableR.FieldPtrs(&rec)
-		ptr := ptrs[colIdx]
-		vals = append(vals, *ptr.(*C))
-	}
+type Selection interface {
+	getExpr() Expr
+	getAlias() string
+}
 
-	return r.colL.In(vals)
+type TypedSelection[F any] interface {
+	Selection
 }
 
 type joinClause struct {
@@ -170,18 +242,19 @@ type BuiltQuery struct {
 
 type Query[R any] struct {
 	ops
-	mapper     RowMapper[R]
-	distinct   bool
-	selections []Selection
-	from       TableLike
-	joins      []joinClause
-	wheres     []Expr
-	groups     []Expr
-	havings    []Expr
-	orders     []Orderer
-	limit      uint
-	offset     uint
-	args       []any
+	mapper      RowMapper[R]
+	distinct    bool
+	selections  []Selection
+	from        TableLike
+	joins       []joinClause
+	wheres      []Expr
+	groups      []Expr
+	havings     []Expr
+	orders      []Orderer
+	limit       uint
+	offset      uint
+	args        []any
+	withDeleted bool
 }
 
 func newQuery[R any](mapper RowMapper[R]) *Query[R] {
@@ -190,6 +263,24 @@ func newQuery[R any](mapper RowMapper[R]) *Query[R] {
 	return q
 }
 
+// withMapper copies the query with a different row mapper.
+func withMapper[R, S any](q *Query[R], mapper RowMapper[S]) *Query[S] {
+	return implOps(&Query[S]{
+		mapper:      mapper,
+		distinct:    q.distinct,
+		selections:  mapper.Selections(),
+		from:        q.from,
+		joins:       slices.Clone(q.joins),
+		wheres:      slices.Clone(q.wheres),
+		groups:      slices.Clone(q.groups),
+		havings:     slices.Clone(q.havings),
+		orders:      slices.Clone(q.orders),
+		limit:       q.limit,
+		offset:      q.offset,
+		withDeleted: q.withDeleted,
+	})
+}
+
 func (q *Query[R]) getPrecedence() int {
 	return prcdValue
 }
@@ -198,81 +289,118 @@ func (q *Query[R]) As(alias string) *QueryTable[R] {
 	return &QueryTable[R]{query: q, alias: alias}
 }
 
+// Clone returns a copy of the query that shares no mutable state with the original.
+func (q *Query[R]) Clone() *Query[R] {
+	c := *q
+	c.selections = slices.Clone(q.selections)
+	c.joins = slices.Clone(q.joins)
+	c.wheres = slices.Clone(q.wheres)
+	c.groups = slices.Clone(q.groups)
+	c.havings = slices.Clone(q.havings)
+	c.orders = slices.Clone(q.orders)
+	c.args = slices.Clone(q.args)
+	return implOps(&c)
+}
+
 func (q *Query[R]) Distinct() *Query[R] {
-	q.distinct = true
-	return q
+	c := q.Clone()
+	c.distinct = true
+	return c
 }
 
 func (q *Query[R]) From(table TableLike) *Query[R] {
-	q.from = table
-	return q
+	c := q.Clone()
+	c.from = table
+	return c
 }
 
 func (q *Query[R]) InnerJoin(table TableLike, condition Expr) *Query[R] {
-	join := joinClause{mode: "INNER", table: table, condition: condition}
-	q.joins = append(q.joins, join)
-	return q
+	return q.join(joinClause{mode: "INNER", table: table, condition: condition})
 }
 
 func (q *Query[R]) LeftJoin(table TableLike, condition Expr) *Query[R] {
-	join := joinClause{mode: "LEFT", table: table, condition: condition}
-	q.joins = append(q.joins, join)
-	return q
+	return q.join(joinClause{mode: "LEFT", table: table, condition: condition})
 }
 
 func (q *Query[R]) RightJoin(table TableLike, condition Expr) *Query[R] {
-	join := joinClause{mode: "RIGHT", table: table, condition: condition}
-	q.joins = append(q.joins, join)
-	return q
+	return q.join(joinClause{mode: "RIGHT", table: table, condition: condition})
 }
 
 func (q *Query[R]) CrossJoin(table TableLike) *Query[R] {
-	join := joinClause{mode: "CROSS", table: table, condition: nil}
-	q.joins = append(q.joins, join)
-	return q
+	return q.join(joinClause{mode: "CROSS", table: table, condition: nil})
 }
 
 func (q *Query[R]) JoinRels(relships ...AnyRelship) *Query[R] {
+	joins := make([]joinClause, 0, len(relships))
 	for _, rs := range relships {
-		join := rs.toJoinClause()
-		switch join.mode {
-		case "INNER":
-			q.joins = append(q.joins, join)
-		default:
-			panic(fmt.Sprintf("unknown join mode: %s", join.mode))
-		}
+		joins = append(joins, rs.toJoinClauses("INNER")...)
 	}
-	return q
+	return q.join(joins...)
+}
+
+func (q *Query[R]) LeftJoinRels(relships ...AnyRelship) *Query[R] {
+	joins := make([]joinClause, 0, len(relships))
+	for _, rs := range relships {
+		joins = append(joins, rs.toJoinClauses("LEFT")...)
+	}
+	return q.join(joins...)
+}
+
+func (q *Query[R]) join(joins ...joinClause) *Query[R] {
+	c := q.Clone()
+	c.joins = append(c.joins, joins...)
+	return c
+}
+
+// WithDeleted includes soft deleted rows, which are excluded by default.
+func (q *Query[R]) WithDeleted() *Query[R] {
+	c := q.Clone()
+	c.withDeleted = true
+	return c
 }
 
 func (q *Query[R]) Where(exprs ...Expr) *Query[R] {
-	q.wheres = append(q.wheres, exprs...)
-	return q
+	c := q.Clone()
+	c.wheres = append(c.wheres, exprs...)
+	return c
+}
+
+// WhereIf adds the conditions only if cond is true.
+func (q *Query[R]) WhereIf(cond bool, exprs ...Expr) *Query[R] {
+	if !cond {
+		return q
+	}
+	return q.Where(exprs...)
 }
 
 func (q *Query[R]) GroupBy(exprs ...Expr) *Query[R] {
-	q.groups = append(q.groups, exprs...)
-	return q
+	c := q.Clone()
+	c.groups = append(c.groups, exprs...)
+	return c
 }
 
 func (q *Query[R]) Having(exprs ...Expr) *Query[R] {
-	q.havings = append(q.havings, exprs...)
-	return q
+	c := q.Clone()
+	c.havings = append(c.havings, exprs...)
+	return c
 }
 
 func (q *Query[R]) OrderBy(orders ...Orderer) *Query[R] {
-	q.orders = orders
-	return q
+	c := q.Clone()
+	c.orders = slices.Clone(orders)
+	return c
 }
 
 func (q *Query[R]) Limit(n uint) *Query[R] {
-	q.limit = n
-	return q
+	c := q.Clone()
+	c.limit = n
+	return c
 }
 
 func (q *Query[R]) Offset(n uint) *Query[R] {
-	q.offset = n
-	return q
+	c := q.Clone()
+	c.offset = n
+	return c
 }
 
 func (q *Query[R]) Build() (bq *BuiltQuery, err error) {
@@ -294,7 +422,7 @@ func (q *Query[R]) BuildWith(cfg *QueryConfig) (bq *BuiltQuery, err error) {
 		sel.getExpr().appendExpr(w, cfg)
 		alias := sel.getAlias()
 		if alias != "" {
-			w.Printf(" AS %s", alias)
+			w.Printf(" AS %s", cfg.dialect.Ident(alias))
 		}
 	}
 
@@ -303,23 +431,14 @@ func (q *Query[R]) BuildWith(cfg *QueryConfig) (bq *BuiltQuery, err error) {
 		q.from.appendTable(w, cfg)
 	}
 
-	if len(q.joins) > 0 {
-		for _, j := range q.joins {
-			w.Write(" ")
-			w.Write(j.mode)
-			w.Write(" JOIN ")
-			j.table.appendTable(w, cfg)
-			if j.mode != "CROSS" {
-				w.Write(" ON ")
-				j.condition.appendExpr(w, cfg)
-			}
-		}
+	joins, wheres := q.joins, q.wheres
+	if !q.withDeleted {
+		joins, wheres = excludeSoftDeleted(q.from, joins, wheres)
 	}
 
-	if len(q.wheres) > 0 {
-		w.Write(" WHERE ")
-		andAll(q.wheres...).appendExpr(w, cfg)
-	}
+	writeJoins(w, cfg, "geq.Query", joins)
+
+	writeConditions(w, cfg, " WHERE ", wheres)
 
 	if len(q.groups) > 0 {
 		w.Write(" GROUP BY ")
@@ -331,28 +450,10 @@ func (q *Query[R]) BuildWith(cfg *QueryConfig) (bq *BuiltQuery, err error) {
 		}
 	}
 
-	if len(q.havings) > 0 {
-		w.Write(" HAVING ")
-		for i, e := range q.havings {
-			if i > 0 {
-				w.Write(", ")
-			}
-			e.appendExpr(w, cfg)
-		}
-	}
+	writeConditions(w, cfg, " HAVING ", q.havings)
 
 	if len(q.orders) > 0 {
-		w.Write(" ORDER BY ")
-		for i, o := range q.orders {
-			if i > 0 {
-				w.Write(", ")
-			}
-			oi := o.order()
-			oi.expr.appendExpr(w, cfg)
-			if oi.order == "DESC" {
-				w.Write(" DESC")
-			}
-		}
+		writeOrders(w, cfg, q.orders)
 	}
 
 	if q.limit > 0 {
@@ -363,27 +464,105 @@ func (q *Query[R]) BuildWith(cfg *QueryConfig) (bq *BuiltQuery, err error) {
 		w.Printf(" OFFSET %d", q.offset)
 	}
 
-	// Check w.errs
-
+	if err := w.Err(); err != nil {
+		return nil, err
+	}
 	return &BuiltQuery{
 		Query: w.String(),
 		Args:  w.Args,
 	}, nil
 }
 
-func andAll(exprs ...Expr) Expr {
-	e := exprs[0]
-	for i := 1; i < len(exprs); i++ {
-		e = e.And(exprs[i])
-	}
-	return e
-}
-
 func (q *Query[R]) Load(ctx context.Context, db QueryRunner) (recs []R, err error) {
 	l := &SliceLoader[R, R]{query: q, mapper: q.mapper}
 	return l.Load(ctx, db)
 }
 
+// Count loads the number of rows of the query.
+func (q *Query[R]) Count(ctx context.Context, db QueryRunner) (int64, error) {
+	return q.CountQuery().LoadOne(ctx, db)
+}
+
+// CountQuery returns a query to count the rows of the query. ORDER BY, LIMIT and OFFSET are ignored.
+func (q *Query[R]) CountQuery() *Query[int64] {
+	mapper := &ValueMapper[int64]{sels: []Selection{Count(Raw("*"))}}
+	base := q.Clone()
+	base.orders = nil
+	base.limit = 0
+	base.offset = 0
+	if base.distinct || len(base.groups) > 0 || len(base.havings) > 0 {
+		return newQuery[int64](mapper).From(base.As("sub"))
+	}
+	return withMapper(base, mapper)
+}
+
+// Exists loads whether the query returns any rows.
+func (q *Query[R]) Exists(ctx context.Context, db QueryRunner) (bool, error) {
+	return q.ExistsQuery().LoadOne(ctx, db)
+}
+
+// ExistsQuery returns a query to check whether the query returns any rows.
+func (q *Query[R]) ExistsQuery() *Query[bool] {
+	mapper := &ValueMapper[bool]{sels: []Selection{Exists(q)}}
+	return newQuery[bool](mapper)
+}
+
+// LoadOne loads the first row of the result. It returns ErrNoRows if no rows found.
+func (q *Query[R]) LoadOne(ctx context.Context, db QueryRunner) (rec R, err error) {
+	recs, err := q.loadUpTo(ctx, db, 1)
+	if err != nil {
+		return rec, err
+	}
+	if len(recs) == 0 {
+		return rec, ErrNoRows
+	}
+	return recs[0], nil
+}
+
+// LoadFirst is same as LoadOne but limits the result to one row in the query.
+func (q *Query[R]) LoadFirst(ctx context.Context, db QueryRunner) (rec R, err error) {
+	return q.Limit(1).LoadOne(ctx, db)
+}
+
+// LoadExactlyOne loads a single row. It returns ErrNoRows if no rows found
+// and ErrMultipleRows if more than one row found.
+func (q *Query[R]) LoadExactlyOne(ctx context.Context, db QueryRunner) (rec R, err error) {
+	recs, err := q.loadUpTo(ctx, db, 2)
+	if err != nil {
+		return rec, err
+	}
+	switch len(recs) {
+	case 0:
+		return rec, ErrNoRows
+	case 1:
+		return recs[0], nil
+	default:
+		return rec, ErrMultipleRows
+	}
+}
+
+func (q *Query[R]) loadUpTo(ctx context.Context, db QueryRunner, n int) (recs []R, err error) {
+	rows, err := q.LoadRows(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	s := &SliceScanner[R]{mapper: q.mapper, dest: &recs}
+	for i := 0; i < n && rows.Next(); i++ {
+		ptrs, err := s.BeforeEachScan(i, q.selections)
+		if err != nil {
+			return nil, err
+		}
+		err = rows.Scan(ptrs...)
+		if err != nil {
+			return nil, err
+		}
+		s.AfterEachScan(ptrs)
+	}
+	return recs, rows.Err()
+}
+
 func (q *Query[R]) LoadRows(ctx context.Context, db QueryRunner) (rows *sql.Rows, err error) {
 	bq, err := q.Build()
 	if err != nil {
@@ -397,14 +576,16 @@ func (q *Query[R]) WillScan(scanners ...RowsScanner) *MultiScanLoader[R] {
 	for _, s := range scanners {
 		sels = append(sels, s.Selections()...)
 	}
-	q.selections = sels
-	return &MultiScanLoader[R]{query: q, scanners: scanners}
+	c := q.Clone()
+	c.selections = sels
+	return &MultiScanLoader[R]{query: c, scanners: scanners}
 }
 
 func (q *Query[R]) appendExpr(w *queryWriter, c *QueryConfig) {
 	bq, err := q.BuildWith(c)
 	if err != nil {
 		w.AddErr(err)
+		return
 	}
 	w.Write("(")
 	w.Write(bq.Query, bq.Args...)
@@ -432,7 +613,7 @@ func (t *QueryTable[R]) appendTable(w *queryWriter, cfg *QueryConfig) {
 	t.query.appendExpr(w, cfg)
 	if t.alias != "" {
 		w.Write(" AS ")
-		w.Write(t.alias)
+		w.Write(cfg.dialect.Ident(t.alias))
 	}
 }
 
@@ -459,6 +640,10 @@ func (w *queryWriter) AddErr(err error) {
 	w.errs = append(w.errs, err)
 }
 
+func (w *queryWriter) Err() error {
+	return errors.Join(w.errs...)
+}
+
 func (w *queryWriter) Printf(format string, fmtargs ...any) {
 	fmt.Fprintf(w.sb, format, fmtargs...)
 }
@@ -0,0 +1,342 @@
+package geq
+
+import (
+	"context"
+	"errors"
+)
+
+type AnyRelship interface {
+	toJoinClauses(mode string) []joinClause
+}
+
+// RelshipOf is a relationship whose right side table has R as its row type.
+type RelshipOf[R any] interface {
+	AnyRelship
+	In(recs []R) Expr
+}
+
+// RelatedLoader is a relationship which can load the right side records related to left side records.
+type RelatedLoader[R any, K comparable] interface {
+	leftTable() AnyTable
+	leftKey() AnyColumn
+	loadRelated(ctx context.Context, db QueryRunner, keys []K) (map[K][]R, error)
+}
+
+// RelshipCond is an additional condition of a relationship.
+type RelshipCond struct {
+	key     relshipKey
+	filter  Expr
+	filterL bool
+}
+
+// RelKey adds a column pair to a relationship for composite keys.
+func RelKey[C any](colL, colR *Column[C]) RelshipCond {
+	return RelshipCond{key: &relshipKeyPair[C]{colL: colL, colR: colR}}
+}
+
+// RelFilterL adds a fixed condition on the left side table to a relationship.
+func RelFilterL(expr Expr) RelshipCond {
+	return RelshipCond{filter: expr, filterL: true}
+}
+
+// RelFilterR adds a fixed condition on the right side table to a relationship.
+func RelFilterR(expr Expr) RelshipCond {
+	return RelshipCond{filter: expr}
+}
+
+type relshipKey interface {
+	left() AnyColumn
+	right() AnyColumn
+	valueOf(ptr any) any
+}
+
+type relshipKeyPair[C any] struct {
+	colL *Column[C]
+	colR *Column[C]
+}
+
+func (p *relshipKeyPair[C]) left() AnyColumn  { return p.colL }
+func (p *relshipKeyPair[C]) right() AnyColumn { return p.colR }
+
+func (p *relshipKeyPair[C]) valueOf(ptr any) any {
+	return *ptr.(*C)
+}
+
+type Relship[T Table[R], R any, C comparable] struct {
+	tableL   AnyTable
+	tableR   T
+	colL     *Column[C]
+	colR     *Column[C]
+	keys     []relshipKey
+	filtersL []Expr
+	filtersR []Expr
+}
+
+func NewRelship[T Table[R], R any, C comparable](
+	tableL AnyTable,
+	tableR T,
+	colL, colR *Column[C],
+	conds ...RelshipCond,
+) *Relship[T, R, C] {
+	r := &Relship[T, R, C]{tableL: tableL, tableR: tableR, colL: colL, colR: colR}
+	r.keys = append(r.keys, &relshipKeyPair[C]{colL: colL, colR: colR})
+	for _, c := range conds {
+		switch {
+		case c.key != nil:
+			r.keys = append(r.keys, c.key)
+		case c.filterL:
+			r.filtersL = append(r.filtersL, c.filter)
+		default:
+			r.filtersR = append(r.filtersR, c.filter)
+		}
+	}
+	return r
+}
+
+func (r *Relship[T, R, C]) Selections() []Selection {
+	return r.tableR.Selections()
+}
+
+func (r *Relship[T, R, C]) FieldPtrs(row *R) []any {
+	return r.tableR.FieldPtrs(row)
+}
+
+func (r *Relship[T, R, C]) appendTable(w *queryWriter, cfg *QueryConfig) {
+	r.tableR.appendTable(w, cfg)
+}
+
+func (r *Relship[T, R, C]) T() T {
+	r.tableR.InitRelships()
+	return r.tableR
+}
+
+func (r *Relship[T, R, C]) toJoinClauses(mode string) []joinClause {
+	conds := make([]Expr, 0, len(r.keys)+len(r.filtersL)+len(r.filtersR))
+	for _, k := range r.keys {
+		conds = append(conds, k.left().Eq(k.right()))
+	}
+	conds = append(conds, r.filtersL...)
+	conds = append(conds, r.filtersR...)
+	return []joinClause{{
+		mode:      mode,
+		table:     r.tableR,
+		condition: andAll(conds...),
+	}}
+}
+
+// In returns a condition to filter the left side table by the given right side records.
+// The fixed conditions on the right side table are not included since the records are already loaded.
+func (r *Relship[T, R, C]) In(recs []R) Expr {
+	sels := r.tableR.Selections()
+	colIdxs := make([]int, 0, len(r.keys))
+	for _, k := range r.keys {
+		colIdx := selectionIndex(sels[0], sels, k.right())
+		if colIdx < 0 {
+			panic("right table column not in selections")
+		}
+		colIdxs = append(colIdxs, colIdx)
+	}
+
+	var cond Expr
+	if len(r.keys) == 1 {
+		vals := make([]C, 0, len(recs))
+		for _, rec := range recs {
+			ptrs := r.tableR.FieldPtrs(&rec)
+			ptr := ptrs[colIdxs[0]]
+			vals = append(vals, *ptr.(*C))
+		}
+		cond = r.colL.In(vals)
+	} else {
+		operands := make([]Expr, 0, len(r.keys))
+		for _, k := range r.keys {
+			operands = append(operands, k.left())
+		}
+		rows := make([][]any, 0, len(recs))
+		for _, rec := range recs {
+			ptrs := r.tableR.FieldPtrs(&rec)
+			row := make([]any, 0, len(r.keys))
+			for i, k := range r.keys {
+				row = append(row, k.valueOf(ptrs[colIdxs[i]]))
+			}
+			rows = append(rows, row)
+		}
+		cond = implOps(&rowInExpr{operands: operands, rows: rows})
+	}
+
+	if len(r.filtersL) == 0 {
+		return cond
+	}
+	return andAll(append([]Expr{cond}, r.filtersL...)...)
+}
+
+func (r *Relship[T, R, C]) leftTable() AnyTable {
+	return r.tableL
+}
+
+func (r *Relship[T, R, C]) leftKey() AnyColumn {
+	return r.colL
+}
+
+func (r *Relship[T, R, C]) loadRelated(ctx context.Context, db QueryRunner, keys []C) (map[C][]R, error) {
+	if len(r.keys) > 1 {
+		return nil, errors.New("[geq.LoadRelated] relationships with composite keys are not supported")
+	}
+	q := SelectFrom[R](r.tableR)
+	if len(r.filtersL) == 0 {
+		q = q.Where(r.colR.In(keys))
+	} else {
+		// Filter the keys by the fixed conditions on the left side table.
+		conds := append([]Expr{r.colL.In(keys)}, r.filtersL...)
+		sub := Select(r.colL).From(r.tableL).Where(conds...)
+		q = q.Where(implOps(&inQueryExpr{operand: r.colR, query: sub}))
+	}
+	if len(r.filtersR) > 0 {
+		q = q.Where(r.filtersR...)
+	}
+	return AsSliceMap(r.colR, q).Load(ctx, db)
+}
+
+// ThroughRelship is a relationship via a join table, typically for many-to-many relationships.
+type ThroughRelship[T Table[R], R any, C comparable] struct {
+	tableL  AnyTable
+	tableR  T
+	via     AnyTable
+	colL    *Column[C]
+	viaColL *Column[C]
+	keyR    relshipKey
+}
+
+func NewThroughRelship[T Table[R], R any, C comparable, D any](
+	tableL AnyTable,
+	tableR T,
+	via AnyTable,
+	colL, viaColL *Column[C],
+	viaColR, colR *Column[D],
+) *ThroughRelship[T, R, C] {
+	return &ThroughRelship[T, R, C]{
+		tableL:  tableL,
+		tableR:  tableR,
+		via:     via,
+		colL:    colL,
+		viaColL: viaColL,
+		keyR:    &relshipKeyPair[D]{colL: viaColR, colR: colR},
+	}
+}
+
+func (r *ThroughRelship[T, R, C]) Selections() []Selection {
+	return r.tableR.Selections()
+}
+
+func (r *ThroughRelship[T, R, C]) FieldPtrs(row *R) []any {
+	return r.tableR.FieldPtrs(row)
+}
+
+func (r *ThroughRelship[T, R, C]) T() T {
+	r.tableR.InitRelships()
+	return r.tableR
+}
+
+func (r *ThroughRelship[T, R, C]) toJoinClauses(mode string) []joinClause {
+	return []joinClause{
+		{mode: mode, table: r.via, condition: r.colL.Eq(r.viaColL)},
+		{mode: mode, table: r.tableR, condition: r.keyR.left().Eq(r.keyR.right())},
+	}
+}
+
+// In returns a condition to filter the left side table by the given right side records
+// using a sub query for the join table.
+// It is always false if recs is empty.
+func (r *ThroughRelship[T, R, C]) In(recs []R) Expr {
+	if len(recs) == 0 {
+		return newRawExpr("1").Eq(newRawExpr("0"))
+	}
+	sels := r.tableR.Selections()
+	colIdx := selectionIndex(sels[0], sels, r.keyR.right())
+	if colIdx < 0 {
+		panic("right table column not in selections")
+	}
+
+	vals := make([]any, 0, len(recs))
+	for _, rec := range recs {
+		ptrs := r.tableR.FieldPtrs(&rec)
+		vals = append(vals, r.keyR.valueOf(ptrs[colIdx]))
+	}
+
+	sub := Select(r.viaColL).From(r.via).Where(r.keyR.left().InAny(vals...))
+	return implOps(&inQueryExpr{operand: r.colL, query: sub})
+}
+
+// LoadMap loads the right side records related to the given left side keys.
+func (r *ThroughRelship[T, R, C]) LoadMap(ctx context.Context, db QueryRunner, keys []C) (recsMap map[C][]R, err error) {
+	if len(keys) == 0 {
+		return map[C][]R{}, nil
+	}
+	sels := append([]Selection{r.viaColL}, r.tableR.Selections()...)
+	q := Select(sels...).From(r.tableR).
+		InnerJoin(r.via, r.keyR.left().Eq(r.keyR.right())).
+		Where(r.viaColL.In(keys)).
+		OrderBy(r.keyR.right().Asc())
+	rows, err := q.LoadRows(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	recsMap = make(map[C][]R)
+	for rows.Next() {
+		var key C
+		var rec R
+		ptrs := append([]any{&key}, r.tableR.FieldPtrs(&rec)...)
+		err = rows.Scan(ptrs...)
+		if err != nil {
+			return nil, err
+		}
+		recsMap[key] = append(recsMap[key], rec)
+	}
+	return recsMap, rows.Err()
+}
+
+func (r *ThroughRelship[T, R, C]) leftTable() AnyTable {
+	return r.tableL
+}
+
+func (r *ThroughRelship[T, R, C]) leftKey() AnyColumn {
+	return r.colL
+}
+
+func (r *ThroughRelship[T, R, C]) loadRelated(ctx context.Context, db QueryRunner, keys []C) (map[C][]R, error) {
+	return r.LoadMap(ctx, db, keys)
+}
+
+// LoadRelated loads the right side records related to the given left side records,
+// grouped by the relationship's key of the right side.
+func LoadRelated[L, R any, K comparable](
+	ctx context.Context,
+	db QueryRunner,
+	srcs []L,
+	relship RelatedLoader[R, K],
+) (map[K][]R, error) {
+	mapper, ok := relship.leftTable().(RowMapper[L])
+	if !ok {
+		return nil, errors.New("[geq.LoadRelated] left side table does not map the given records")
+	}
+	sels := mapper.Selections()
+	colIdx := selectionIndex(sels[0], sels, relship.leftKey())
+	if colIdx < 0 {
+		return nil, errors.New("[geq.LoadRelated] left table column not in selections")
+	}
+
+	keys := make([]K, 0, len(srcs))
+	seen := make(map[K]struct{}, len(srcs))
+	for _, src := range srcs {
+		key := *mapper.FieldPtrs(&src)[colIdx].(*K)
+		if _, ok := seen[key]; !ok {
+			seen[key] = struct{}{}
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return map[K][]R{}, nil
+	}
+	return relship.loadRelated(ctx, db, keys)
+}
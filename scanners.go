@@ -92,3 +92,112 @@ func (s *SliceMapScanner[R, K]) AfterEachScan(ptrs []any) {
 	key := *keyPtr.(*K)
 	(*s.dest)[key] = append((*s.dest)[key], *s.row)
 }
+
+// rowsRescanner is implemented by scanners which cannot scan a row at once,
+// such as the ones for outer joined tables whose columns may be NULL.
+// They first receive values by probe pointers and then decide the pointers to scan the row again.
+type rowsRescanner interface {
+	rescanPtrs(ptrs []any) (newPtrs []any, rescan bool)
+}
+
+// nullProbe accepts any value and records whether it is NULL.
+type nullProbe struct {
+	null bool
+}
+
+func (p *nullProbe) Scan(src any) error {
+	p.null = src == nil
+	return nil
+}
+
+func newNullProbes(n int) []any {
+	probes := make([]any, n)
+	for i := range probes {
+		probes[i] = new(nullProbe)
+	}
+	return probes
+}
+
+func isProbedNull(ptrs []any, idx int) bool {
+	p, ok := ptrs[idx].(*nullProbe)
+	return ok && p.null
+}
+
+type OptionalSliceScanner[R any] struct {
+	mapper RowMapper[R]
+	key    Selection
+	dest   *[]*R
+	keyIdx int
+	row    *R
+}
+
+func (s *OptionalSliceScanner[R]) Selections() []Selection {
+	return s.mapper.Selections()
+}
+
+func (s *OptionalSliceScanner[R]) BeforeEachScan(idx int, sels []Selection) (ptrs []any, err error) {
+	if idx == 0 {
+		ki := selectionIndex(s.Selections()[0], sels, s.key)
+		if ki == -1 {
+			return nil, errors.New("failed to scan as optional slice: key not found in selections")
+		}
+		s.keyIdx = ki
+		*s.dest = make([]*R, 0)
+	}
+	s.row = nil
+	return newNullProbes(len(s.Selections())), nil
+}
+
+func (s *OptionalSliceScanner[R]) rescanPtrs(ptrs []any) (newPtrs []any, rescan bool) {
+	if isProbedNull(ptrs, s.keyIdx) {
+		return ptrs, false
+	}
+	s.row = new(R)
+	return s.mapper.FieldPtrs(s.row), true
+}
+
+func (s *OptionalSliceScanner[R]) AfterEachScan(ptrs []any) {
+	*s.dest = append(*s.dest, s.row)
+}
+
+type OptionalMapScanner[R any, K comparable] struct {
+	mapper RowMapper[R]
+	key    TypedSelection[K]
+	dest   *map[K]R
+	keyIdx int
+	row    *R
+}
+
+func (s *OptionalMapScanner[R, K]) Selections() []Selection {
+	return s.mapper.Selections()
+}
+
+func (s *OptionalMapScanner[R, K]) BeforeEachScan(idx int, sels []Selection) (ptrs []any, err error) {
+	if idx == 0 {
+		ki := selectionIndex(s.Selections()[0], sels, s.key)
+		if ki == -1 {
+			return nil, errors.New("failed to scan as optional map: key not found in selections")
+		}
+		s.keyIdx = ki
+		*s.dest = make(map[K]R)
+	}
+	s.row = nil
+	return newNullProbes(len(s.Selections())), nil
+}
+
+func (s *OptionalMapScanner[R, K]) rescanPtrs(ptrs []any) (newPtrs []any, rescan bool) {
+	if isProbedNull(ptrs, s.keyIdx) {
+		return ptrs, false
+	}
+	s.row = new(R)
+	return s.mapper.FieldPtrs(s.row), true
+}
+
+func (s *OptionalMapScanner[R, K]) AfterEachScan(ptrs []any) {
+	if s.row == nil {
+		return
+	}
+	keyPtr := ptrs[s.keyIdx]
+	key := *keyPtr.(*K)
+	(*s.dest)[key] = *s.row
+}
@@ -4,25 +4,54 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
 )
 
 type UpdateQuery struct {
 	table    AnyTable
 	valueMap map[AnyColumn]Expr
 	wheres   []Expr
+	from     []TableLike
+	joins    []joinClause
+	orders   []Orderer
+	limit    uint
+	all      bool
+	noop     bool
+	rowCond  Expr
+	lockCond Expr
+	err      error
 }
 
 func newUpdateQuery(table AnyTable) *UpdateQuery {
 	return &UpdateQuery{table: table}
 }
 
+// Clone returns a copy of the query that shares no mutable state with the original.
+func (q *UpdateQuery) Clone() *UpdateQuery {
+	c := *q
+	c.valueMap = maps.Clone(q.valueMap)
+	c.wheres = slices.Clone(q.wheres)
+	c.from = slices.Clone(q.from)
+	c.joins = slices.Clone(q.joins)
+	c.orders = slices.Clone(q.orders)
+	return &c
+}
+
 func (q *UpdateQuery) Set(pairs ...ValuePair) *UpdateQuery {
 	m := make(map[AnyColumn]Expr, len(pairs))
 	for _, p := range pairs {
 		m[p.column] = p.value
 	}
-	q.valueMap = m
-	return q
+	c := q.Clone()
+	c.valueMap = m
+	c.noop = false
+	c.rowCond = nil
+	c.lockCond = nil
+	c.err = nil
+	return c
 }
 
 func (q *UpdateQuery) SetMap(vm ValueMap) *UpdateQuery {
@@ -30,13 +59,178 @@ func (q *UpdateQuery) SetMap(vm ValueMap) *UpdateQuery {
 	for k, v := range vm {
 		em[k] = toExpr(v)
 	}
-	q.valueMap = em
-	return q
+	c := q.Clone()
+	c.valueMap = em
+	c.noop = false
+	c.rowCond = nil
+	c.lockCond = nil
+	c.err = nil
+	return c
+}
+
+// SetRow sets all the column values of the row except the primary key and the automatic timestamps,
+// and limits the update to the row by its primary key.
+// The row must be a value or a pointer of the row type of the table.
+// If the table has a version column, the query increments the version only when
+// the version is not changed from the row, and Exec returns ErrStaleRecord otherwise.
+func (q *UpdateQuery) SetRow(row any) *UpdateQuery {
+	ptrs, err := rowFieldPtrs(q.table, row)
+	if err != nil {
+		return q.withErr(err)
+	}
+	return q.setFields(ptrs, nil)
+}
+
+// SetChanged sets only the column values changed from before to after,
+// and limits the update to the row by the primary key of before.
+// If nothing is changed, Exec does nothing. The version column is checked
+// against the version of before in the same way as SetRow.
+func (q *UpdateQuery) SetChanged(before, after any) *UpdateQuery {
+	basePtrs, err := rowFieldPtrs(q.table, before)
+	if err != nil {
+		return q.withErr(err)
+	}
+	ptrs, err := rowFieldPtrs(q.table, after)
+	if err != nil {
+		return q.withErr(err)
+	}
+	return q.setFields(ptrs, basePtrs)
+}
+
+func (q *UpdateQuery) withErr(err error) *UpdateQuery {
+	c := q.Clone()
+	c.err = err
+	return c
+}
+
+// rowFieldPtrs returns the field pointers of the row by the FieldPtrs method of the table.
+func rowFieldPtrs(table AnyTable, row any) ([]any, error) {
+	m := reflect.ValueOf(table).MethodByName("FieldPtrs")
+	if !m.IsValid() || m.Type().NumIn() != 1 || m.Type().In(0).Kind() != reflect.Pointer {
+		return nil, errors.New("[geq.Update] table has no FieldPtrs method")
+	}
+	ptrType := m.Type().In(0)
+	rv := reflect.ValueOf(row)
+	switch {
+	case rv.IsValid() && rv.Type() == ptrType.Elem():
+		ptr := reflect.New(ptrType.Elem())
+		ptr.Elem().Set(rv)
+		rv = ptr
+	case rv.IsValid() && rv.Type() == ptrType && !rv.IsNil():
+		ptr := reflect.New(ptrType.Elem())
+		ptr.Elem().Set(rv.Elem())
+		rv = ptr
+	default:
+		return nil, fmt.Errorf("[geq.Update] row type %T does not match table row type %s", row, ptrType.Elem())
+	}
+	ptrs, _ := m.Call([]reflect.Value{rv})[0].Interface().([]any)
+	return ptrs, nil
+}
+
+func (q *UpdateQuery) setFields(ptrs, basePtrs []any) *UpdateQuery {
+	pks := q.table.getPKs()
+	version := q.table.getVersion()
+	var lockCond Expr
+	m := make(map[AnyColumn]Expr, len(ptrs))
+	autoTimes := []AnyColumn{q.table.getAutoCreateTime(), q.table.getAutoUpdateTime()}
+	pkConds := make([]Expr, 0, len(pks))
+	for i, col := range q.table.getColumns() {
+		if slices.Contains(pks, col) {
+			cur := ptrs[i]
+			if basePtrs != nil {
+				cur = basePtrs[i]
+			}
+			pkConds = append(pkConds, col.Eq(col.fieldValue(cur)))
+			continue
+		}
+		if slices.Contains(autoTimes, col) {
+			continue
+		}
+		if col == version {
+			cur := ptrs[i]
+			if basePtrs != nil {
+				cur = basePtrs[i]
+			}
+			lockCond = col.Eq(col.fieldValue(cur))
+			continue
+		}
+		v := col.fieldValue(ptrs[i])
+		if basePtrs != nil && reflect.DeepEqual(v, col.fieldValue(basePtrs[i])) {
+			continue
+		}
+		m[col] = toExpr(v)
+	}
+	noop := basePtrs != nil && len(m) == 0
+	if lockCond != nil && !noop {
+		m[version] = version.Add(1)
+	}
+	c := q.Clone()
+	c.valueMap = m
+	c.noop = noop
+	c.rowCond = andAll(pkConds...)
+	c.lockCond = lockCond
+	c.err = nil
+	return c
 }
 
 func (q *UpdateQuery) Where(exprs ...Expr) *UpdateQuery {
-	q.wheres = append(q.wheres, exprs...)
-	return q
+	c := q.Clone()
+	c.wheres = append(c.wheres, exprs...)
+	return c
+}
+
+// From adds tables referenced by the update conditions.
+// It is rendered as UPDATE ... FROM on PostgreSQL and UPDATE a, b ... on MySQL.
+func (q *UpdateQuery) From(tables ...TableLike) *UpdateQuery {
+	c := q.Clone()
+	c.from = append(c.from, tables...)
+	return c
+}
+
+// InnerJoin joins a table to update rows. Only dialects such as MySQL support it.
+func (q *UpdateQuery) InnerJoin(table TableLike, condition Expr) *UpdateQuery {
+	return q.join(joinClause{mode: "INNER", table: table, condition: condition})
+}
+
+// LeftJoin joins a table to update rows. Only dialects such as MySQL support it.
+func (q *UpdateQuery) LeftJoin(table TableLike, condition Expr) *UpdateQuery {
+	return q.join(joinClause{mode: "LEFT", table: table, condition: condition})
+}
+
+func (q *UpdateQuery) JoinRels(relships ...AnyRelship) *UpdateQuery {
+	joins := make([]joinClause, 0, len(relships))
+	for _, rs := range relships {
+		joins = append(joins, rs.toJoinClauses("INNER")...)
+	}
+	return q.join(joins...)
+}
+
+func (q *UpdateQuery) join(joins ...joinClause) *UpdateQuery {
+	c := q.Clone()
+	c.joins = append(c.joins, joins...)
+	return c
+}
+
+// OrderBy sets the order to update rows. Only dialects such as MySQL support it.
+func (q *UpdateQuery) OrderBy(orders ...Orderer) *UpdateQuery {
+	c := q.Clone()
+	c.orders = slices.Clone(orders)
+	return c
+}
+
+// Limit limits the number of rows to update. Only dialects such as MySQL support it.
+func (q *UpdateQuery) Limit(n uint) *UpdateQuery {
+	c := q.Clone()
+	c.limit = n
+	return c
+}
+
+// All allows the query to update all rows without WHERE conditions.
+// Otherwise the query without WHERE conditions fails to build.
+func (q *UpdateQuery) All() *UpdateQuery {
+	c := q.Clone()
+	c.all = true
+	return c
 }
 
 func (q *UpdateQuery) Build() (bq *BuiltQuery, err error) {
@@ -45,46 +239,135 @@ func (q *UpdateQuery) Build() (bq *BuiltQuery, err error) {
 }
 
 func (q *UpdateQuery) BuildWith(cfg *QueryConfig) (bq *BuiltQuery, err error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	if len(q.valueMap) == 0 {
+		return nil, errors.New("[geq.Update] values empty")
+	}
 	w := newQueryWriter()
+	q.write(w, cfg)
+	if err := w.Err(); err != nil {
+		return nil, err
+	}
+	return &BuiltQuery{Query: w.String(), Args: w.Args}, nil
+}
+
+func (q *UpdateQuery) write(w *queryWriter, cfg *QueryConfig) {
+	style := modifyStyle(cfg.dialect)
+	multiTable := len(q.from) > 0 || len(q.joins) > 0
+
 	w.Write("UPDATE ")
-	w.Write(cfg.dialect.Ident(q.table.getTableName()))
+	q.table.appendTable(w, cfg)
+
+	switch style {
+	case ModifyJoin:
+		for _, t := range q.from {
+			w.Write(", ")
+			t.appendTable(w, cfg)
+		}
+		writeJoins(w, cfg, "geq.Update", q.joins)
+	default:
+		if len(q.joins) > 0 {
+			w.AddErr(errors.New("[geq.Update] JOIN is not supported by the dialect, use From instead"))
+		}
+	}
+
 	w.Write(" SET ")
 
-	if len(q.valueMap) == 0 {
-		return nil, errors.New("[geq.Update] values empty")
+	valueMap := q.valueMap
+	if c := q.table.getAutoUpdateTime(); c != nil {
+		if _, ok := valueMap[c]; !ok {
+			valueMap = maps.Clone(valueMap)
+			valueMap[c] = toExpr(currentTime())
+		}
 	}
 
 	setWritten := false
 	for _, c := range q.table.getColumns() {
+		v, ok := valueMap[c]
+		if !ok {
+			continue
+		}
 		if setWritten {
 			w.Write(", ")
 		}
-		v, ok := q.valueMap[c]
-		if ok {
-			setWritten = true
+		setWritten = true
+		// Qualify the column names since joined tables may have the same names.
+		if style == ModifyJoin && multiTable {
+			c.appendExpr(w, cfg)
+		} else {
 			w.Write(cfg.dialect.Ident(c.getColumnName()))
-			w.Write(" = ")
-			v.appendExpr(w, cfg)
 		}
+		w.Write(" = ")
+		v.appendExpr(w, cfg)
 	}
 
-	if len(q.wheres) > 0 {
-		w.Write(" WHERE ")
-		for i, e := range q.wheres {
+	if style != ModifyJoin && len(q.from) > 0 {
+		w.Write(" FROM ")
+		for i, t := range q.from {
 			if i > 0 {
-				w.Write(" AND ")
+				w.Write(", ")
 			}
-			e.appendExpr(w, cfg)
+			t.appendTable(w, cfg)
 		}
 	}
 
-	return &BuiltQuery{Query: w.String(), Args: w.Args}, nil
+	if andAll(q.wheres...) == nil && q.rowCond == nil && !q.all {
+		w.AddErr(errors.New("[geq.Update] WHERE conditions empty, use All to update all rows"))
+	}
+	conds := append(slices.Clone(q.wheres), q.rowCond, q.lockCond)
+	writeConditions(w, cfg, " WHERE ", conds)
+
+	if len(q.orders) > 0 || q.limit > 0 {
+		switch {
+		case style != ModifyJoin:
+			w.AddErr(errors.New("[geq.Update] ORDER BY and LIMIT are not supported by the dialect"))
+		case multiTable:
+			w.AddErr(errors.New("[geq.Update] ORDER BY and LIMIT cannot be used with multiple tables"))
+		}
+	}
+	if len(q.orders) > 0 {
+		writeOrders(w, cfg, q.orders)
+	}
+	if q.limit > 0 {
+		w.Printf(" LIMIT %d", q.limit)
+	}
 }
 
 func (q *UpdateQuery) Exec(ctx context.Context, db QueryExecutor) (result sql.Result, err error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	if q.noop {
+		// Validate the query even though it has nothing to change.
+		w := newQueryWriter()
+		q.write(w, &QueryConfig{dialect: defaultDialect})
+		if err := w.Err(); err != nil {
+			return nil, err
+		}
+		return noopResult{}, nil
+	}
 	bq, err := q.Build()
 	if err != nil {
 		return nil, err
 	}
-	return db.ExecContext(ctx, bq.Query, bq.Args...)
+	result, err = db.ExecContext(ctx, bq.Query, bq.Args...)
+	if err != nil || q.lockCond == nil {
+		return result, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, ErrStaleRecord
+	}
+	return result, nil
 }
+
+// noopResult is a result of an update which has nothing to change.
+type noopResult struct{}
+
+func (noopResult) LastInsertId() (int64, error) { return 0, nil }
+func (noopResult) RowsAffected() (int64, error) { return 0, nil }